@@ -7,12 +7,25 @@
 // Additionally, command handlers and event appliers should be defined within
 // the user's domain aggregate. The command handlers handle commands and produce
 // events, while the event appliers apply events to update the aggregate's state.
+// An event applier may return an error if the event cannot be applied, e.g. when
+// it reveals a corrupt history or an impossible state transition.
 //
 // Once the user's domain aggregate is defined, it can be transformed into
 // a cqrs.ESAggregate using the aggregate.FromAggregate function. This function
 // automatically registers the command handlers and event appliers defined in
 // the user's domain aggregate.
 //
+// By default a command handler must be named after the command type and an event
+// applier must be named "On" followed by the event type. Other conventions can be
+// configured with the aggregate.WithNaming option, see aggregate.MethodNaming.
+//
+// FromAggregate silently skips the methods which do not follow the conventions.
+// Use aggregate.FromAggregateStrict or aggregate.Verify to get a detailed report
+// of the malformed handlers and appliers instead.
+//
+// Alternatively, an aggregate can be modelled with pure functions as an
+// aggregate.Decider and turned into a cqrs.ESAggregate with aggregate.FromDecider.
+//
 // For a detailed example of how to use the aggregate package, please refer to
 // the Example function in the example_test.go file.
 //
@@ -21,6 +34,8 @@ package aggregate
 
 import (
 	"errors"
+
+	"github.com/screwyprof/cqrs/naming"
 )
 
 var (
@@ -32,4 +47,15 @@ var (
 
 	// ErrAggregateNotRegistered is returned when an aggregate is not registered in the factory.
 	ErrAggregateNotRegistered = errors.New("aggregate is not registered")
+
+	// ErrTypeCollision is returned when a command or an event is routed to a method which accepts another type.
+	// It is an alias of naming.ErrTypeCollision.
+	ErrTypeCollision = naming.ErrTypeCollision
+
+	// ErrConventionViolation is returned when an aggregate violates the handler or applier conventions.
+	ErrConventionViolation = errors.New("aggregate violates conventions")
+
+	// ErrInconsistentAggregate is returned by an aggregate whose state cannot be restored
+	// after some of the events of a batch have been applied, see EventSourced.Handle.
+	ErrInconsistentAggregate = errors.New("aggregate state is inconsistent")
 )
@@ -7,7 +7,8 @@ import (
 )
 
 var (
-	ErrItCanHappenOnceOnly = errors.New("some business rule error occurred")
+	ErrItCanHappenOnceOnly  = errors.New("some business rule error occurred")
+	ErrImpossibleTransition = errors.New("impossible state transition")
 
 	TestAggregateType = "mock.TestAggregate" //nolint:gochecknoglobals
 )
@@ -54,3 +55,7 @@ func (a *TestAggregate) OnSomethingHappened(_ SomethingHappened) {
 
 func (a *TestAggregate) OnSomethingElseHappened(_ SomethingElseHappened) {
 }
+
+func (a *TestAggregate) OnSomethingImpossibleHappened(_ SomethingImpossibleHappened) error {
+	return ErrImpossibleTransition
+}
@@ -19,3 +19,20 @@ func (c MakeSomethingHappen) AggregateType() string {
 func (c MakeSomethingHappen) CommandType() string {
 	return "MakeSomethingHappen"
 }
+
+// MakeSomethingHappenElsewhere shares its command type with MakeSomethingHappen as if it came from another package.
+type MakeSomethingHappenElsewhere struct {
+	AggID cqrs.Identifier
+}
+
+func (c MakeSomethingHappenElsewhere) AggregateID() cqrs.Identifier {
+	return c.AggID
+}
+
+func (c MakeSomethingHappenElsewhere) AggregateType() string {
+	return "mock.TestAggregate"
+}
+
+func (c MakeSomethingHappenElsewhere) CommandType() string {
+	return "MakeSomethingHappen"
+}
@@ -0,0 +1,31 @@
+package aggtest
+
+import "github.com/screwyprof/cqrs/aggregate"
+
+// TestState is the state of the functional counterpart of TestAggregate.
+type TestState struct {
+	AlreadyHappened bool
+}
+
+// NewTestDecider creates the functional counterpart of TestAggregate used for testing.
+func NewTestDecider() aggregate.Decider[TestState, MakeSomethingHappen, Event] {
+	return aggregate.Decider[TestState, MakeSomethingHappen, Event]{
+		Decide: func(state TestState, _ MakeSomethingHappen) ([]Event, error) {
+			if state.AlreadyHappened {
+				return nil, ErrItCanHappenOnceOnly
+			}
+
+			return []Event{SomethingHappened{}}, nil
+		},
+		Evolve: func(state TestState, e Event) TestState {
+			if _, ok := e.(SomethingHappened); ok {
+				state.AlreadyHappened = true
+			}
+
+			return state
+		},
+		Initial: func() TestState {
+			return TestState{}
+		},
+	}
+}
@@ -13,3 +13,24 @@ type SomethingElseHappened struct{}
 func (c SomethingElseHappened) EventType() string {
 	return "SomethingElseHappened"
 }
+
+type SomethingHappenedV2 struct {
+	Data string
+}
+
+func (c SomethingHappenedV2) EventType() string {
+	return "something-happened.v2"
+}
+
+// SomethingHappenedElsewhere shares its event type with SomethingHappened as if it came from another package.
+type SomethingHappenedElsewhere struct{}
+
+func (c SomethingHappenedElsewhere) EventType() string {
+	return "SomethingHappened"
+}
+
+type SomethingImpossibleHappened struct{}
+
+func (c SomethingImpossibleHappened) EventType() string {
+	return "SomethingImpossibleHappened"
+}
@@ -0,0 +1,42 @@
+package aggtest
+
+// MalformedAggregateType is the type of MalformedAggregate.
+const MalformedAggregateType = "mock.MalformedAggregate"
+
+// MalformedAggregate is an aggregate which violates the handler and applier conventions, used for testing.
+type MalformedAggregate struct {
+	id Identifier
+}
+
+// NewMalformedAggregate creates a new instance of MalformedAggregate.
+func NewMalformedAggregate(id Identifier) *MalformedAggregate {
+	return &MalformedAggregate{id: id}
+}
+
+// AggregateID implements cqrs.Aggregate interface.
+func (a *MalformedAggregate) AggregateID() Identifier {
+	return a.id
+}
+
+// AggregateType implements cqrs.Aggregate interface.
+func (a *MalformedAggregate) AggregateType() string {
+	return MalformedAggregateType
+}
+
+// MakeSomethingHappen does not return an error.
+func (a *MalformedAggregate) MakeSomethingHappen(_ MakeSomethingHappen) []Event {
+	return nil
+}
+
+// MakeSomethingHappenAgain handles the same command type under a name that never matches it.
+func (a *MalformedAggregate) MakeSomethingHappenAgain(_ MakeSomethingHappen) ([]Event, error) {
+	return nil, nil
+}
+
+// OnSomethingHappened accepts something which is not an event.
+func (a *MalformedAggregate) OnSomethingHappened(_ string) {
+}
+
+// OnSomethingStrangeHappened is named after an event it does not accept.
+func (a *MalformedAggregate) OnSomethingStrangeHappened(_ SomethingElseHappened) {
+}
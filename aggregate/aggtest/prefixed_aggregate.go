@@ -0,0 +1,38 @@
+package aggtest
+
+// PrefixedAggregateType is the type of PrefixedAggregate.
+const PrefixedAggregateType = "mock.PrefixedAggregate"
+
+// PrefixedAggregate is an aggregate which names its handlers Handle<Command> and its appliers Apply<Event>.
+type PrefixedAggregate struct {
+	id Identifier
+
+	Happened []string
+}
+
+// NewPrefixedAggregate creates a new instance of PrefixedAggregate.
+func NewPrefixedAggregate(id Identifier) *PrefixedAggregate {
+	return &PrefixedAggregate{id: id}
+}
+
+// AggregateID implements cqrs.Aggregate interface.
+func (a *PrefixedAggregate) AggregateID() Identifier {
+	return a.id
+}
+
+// AggregateType implements cqrs.Aggregate interface.
+func (a *PrefixedAggregate) AggregateType() string {
+	return PrefixedAggregateType
+}
+
+func (a *PrefixedAggregate) HandleMakeSomethingHappen(_ MakeSomethingHappen) ([]Event, error) {
+	return []Event{SomethingHappenedV2{Data: "v2"}}, nil
+}
+
+func (a *PrefixedAggregate) ApplySomethingHappened(e SomethingHappened) {
+	a.Happened = append(a.Happened, e.Data)
+}
+
+func (a *PrefixedAggregate) ApplySomethingHappenedV2(e SomethingHappenedV2) {
+	a.Happened = append(a.Happened, e.Data)
+}
@@ -0,0 +1,222 @@
+// Package proptest checks aggregates against random sequences of commands.
+//
+// The commands are produced by the given generators and handled by an aggregate.EventSourced.
+// The invariants are checked after each command, and the produced events are replayed
+// on a fresh instance which must end up in the same state.
+// A failing sequence is shrunk to a minimal one before it is reported.
+package proptest
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/screwyprof/cqrs"
+	"github.com/screwyprof/cqrs/aggregate"
+)
+
+const (
+	defaultRuns        = 100
+	defaultMaxCommands = 20
+)
+
+// TestingT is the part of *testing.T the harness uses.
+type TestingT interface {
+	Errorf(format string, args ...any)
+	Helper()
+}
+
+// Generator produces a random command addressed to the aggregate with the given identifier.
+type Generator func(rnd *rand.Rand, id cqrs.Identifier) cqrs.Command
+
+// Invariant checks the state of the aggregate, it returns an error describing the violation.
+type Invariant[A cqrs.Aggregate] func(agg A) error
+
+// Property describes the aggregate under test.
+type Property[A cqrs.Aggregate] struct {
+	// New creates a fresh instance of the aggregate.
+	New func(id cqrs.Identifier) A
+
+	// Commands are the generators the random commands are picked from.
+	Commands []Generator
+
+	// Invariants are checked after each command.
+	Invariants []Invariant[A]
+}
+
+// Option configures Check.
+type Option func(*config)
+
+type config struct {
+	runs        int
+	maxCommands int
+	seed        uint64
+	id          cqrs.Identifier
+	opts        []aggregate.Option
+}
+
+// WithRuns sets how many random sequences are checked.
+func WithRuns(runs int) Option {
+	return func(c *config) {
+		c.runs = runs
+	}
+}
+
+// WithMaxCommands sets the maximum length of a random sequence.
+func WithMaxCommands(maxCommands int) Option {
+	return func(c *config) {
+		c.maxCommands = maxCommands
+	}
+}
+
+// WithSeed sets the seed of the random sequences, e.g. to reproduce a reported failure.
+//
+// A time based seed is used by default.
+func WithSeed(seed uint64) Option {
+	return func(c *config) {
+		c.seed = seed
+	}
+}
+
+// WithAggregateOptions sets the options the aggregate is turned into an aggregate.EventSourced with.
+func WithAggregateOptions(opts ...aggregate.Option) Option {
+	return func(c *config) {
+		c.opts = opts
+	}
+}
+
+// Check runs random sequences of commands against the aggregate and reports the minimal failing one.
+//
+// The commands rejected by the aggregate are part of the sequence, they are expected to leave the state intact.
+func Check[A cqrs.Aggregate](t TestingT, p Property[A], opts ...Option) {
+	t.Helper()
+
+	if p.New == nil {
+		panic("new is required")
+	}
+
+	if len(p.Commands) == 0 {
+		panic("commands are required")
+	}
+
+	c := &config{
+		runs:        defaultRuns,
+		maxCommands: defaultMaxCommands,
+		seed:        uint64(time.Now().UnixNano()), //nolint:gosec
+		id:          identifier("proptest"),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	for run := range c.runs {
+		rnd := rand.New(rand.NewPCG(c.seed, uint64(run))) //nolint:gosec
+
+		commands := generate(rnd, p.Commands, c.id, 1+rnd.IntN(max(c.maxCommands, 1)))
+		if err := execute(p, c, commands); err != nil {
+			minimal, err := shrink(p, c, commands, err)
+
+			t.Errorf("property failed (seed %d, run %d): %v\nminimal command sequence:\n%s",
+				c.seed, run, err, describeCommands(minimal))
+
+			return
+		}
+	}
+}
+
+func generate(rnd *rand.Rand, generators []Generator, id cqrs.Identifier, n int) []cqrs.Command {
+	commands := make([]cqrs.Command, 0, n)
+	for range n {
+		commands = append(commands, generators[rnd.IntN(len(generators))](rnd, id))
+	}
+
+	return commands
+}
+
+// execute runs the commands and returns an error describing the first failed check.
+func execute[A cqrs.Aggregate](p Property[A], c *config, commands []cqrs.Command) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	// the factory lets the aggregate restore its state when the events of a command cannot be applied.
+	f := aggregate.NewFactory(c.opts...)
+	f.RegisterAggregate("proptest", func(id cqrs.Identifier) cqrs.ESAggregate {
+		return f.FromAggregate(p.New(id))
+	})
+
+	created, err := f.CreateAggregate("proptest", c.id)
+	if err != nil {
+		return err
+	}
+
+	es, _ := created.(*aggregate.EventSourced)
+	agg, _ := es.Aggregate.(A)
+
+	for i, cmd := range commands {
+		_, _ = es.Handle(cmd)
+
+		for _, invariant := range p.Invariants {
+			if err := invariant(agg); err != nil {
+				return fmt.Errorf("invariant violated after command #%d %T: %w", i, cmd, err)
+			}
+		}
+	}
+
+	replayed := p.New(c.id)
+	if err := aggregate.FromAggregate(replayed, c.opts...).Apply(es.Changes()...); err != nil {
+		return fmt.Errorf("cannot replay the events: %w", err)
+	}
+
+	if !reflect.DeepEqual(agg, replayed) {
+		return fmt.Errorf("replayed state differs:\nhandled:  %+v\nreplayed: %+v", agg, replayed)
+	}
+
+	return nil
+}
+
+// shrink removes the commands which are not needed for the sequence to fail, the bigger chunks first.
+func shrink[A cqrs.Aggregate](p Property[A], c *config, commands []cqrs.Command, err error) ([]cqrs.Command, error) {
+	for chunk := len(commands) / 2; chunk >= 1; {
+		shrunk := false
+
+		for start := 0; start+chunk <= len(commands); {
+			candidate := append(commands[:start:start], commands[start+chunk:]...)
+
+			if candidateErr := execute(p, c, candidate); candidateErr != nil {
+				commands, err, shrunk = candidate, candidateErr, true
+
+				continue
+			}
+
+			start++
+		}
+
+		if !shrunk {
+			chunk /= 2
+		}
+	}
+
+	return commands, err
+}
+
+func describeCommands(commands []cqrs.Command) string {
+	var b strings.Builder
+	for i, cmd := range commands {
+		fmt.Fprintf(&b, "\t#%d %#v\n", i, cmd)
+	}
+
+	return b.String()
+}
+
+// identifier is the identifier of the aggregate under test.
+type identifier string
+
+func (i identifier) String() string {
+	return string(i)
+}
@@ -0,0 +1,177 @@
+package proptest_test
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/cqrs"
+	"github.com/screwyprof/cqrs/aggregate/aggtest"
+	"github.com/screwyprof/cqrs/aggregate/aggtest/proptest"
+)
+
+var errTooMany = errors.New("too many")
+
+// recordingT records the failures instead of failing the test.
+type recordingT struct {
+	failures []string
+}
+
+func (t *recordingT) Errorf(format string, args ...any) {
+	t.failures = append(t.failures, fmt.Sprintf(format, args...))
+}
+
+func (t *recordingT) Helper() {}
+
+// counter counts up to a limit, but forgets to check the limit when it counts twice at once.
+type counter struct {
+	id    cqrs.Identifier
+	count int
+	limit int
+}
+
+func (c *counter) AggregateID() cqrs.Identifier { return c.id }
+func (c *counter) AggregateType() string        { return "counter" }
+
+func (c *counter) Increment(_ increment) ([]aggtest.Event, error) {
+	if c.count >= c.limit {
+		return nil, errTooMany
+	}
+
+	return []aggtest.Event{incremented{}}, nil
+}
+
+func (c *counter) IncrementTwice(_ incrementTwice) ([]aggtest.Event, error) {
+	return []aggtest.Event{incremented{}, incremented{}}, nil
+}
+
+func (c *counter) Noop(_ noop) ([]aggtest.Event, error) {
+	return nil, nil
+}
+
+func (c *counter) OnIncremented(_ incremented) {
+	c.count++
+}
+
+type increment struct{ ID cqrs.Identifier }
+
+func (c increment) AggregateID() cqrs.Identifier { return c.ID }
+func (c increment) AggregateType() string        { return "counter" }
+func (c increment) CommandType() string          { return "Increment" }
+
+type incrementTwice struct{ ID cqrs.Identifier }
+
+func (c incrementTwice) AggregateID() cqrs.Identifier { return c.ID }
+func (c incrementTwice) AggregateType() string        { return "counter" }
+func (c incrementTwice) CommandType() string          { return "IncrementTwice" }
+
+type noop struct{ ID cqrs.Identifier }
+
+func (c noop) AggregateID() cqrs.Identifier { return c.ID }
+func (c noop) AggregateType() string        { return "counter" }
+func (c noop) CommandType() string          { return "Noop" }
+
+type incremented struct{}
+
+func (e incremented) EventType() string { return "Incremented" }
+
+func newCounter(id cqrs.Identifier) *counter {
+	return &counter{id: id, limit: 3}
+}
+
+func withinLimit(c *counter) error {
+	if c.count > c.limit {
+		return fmt.Errorf("count %d is over the limit %d", c.count, c.limit)
+	}
+
+	return nil
+}
+
+func incrementGen(_ *rand.Rand, id cqrs.Identifier) cqrs.Command { return increment{ID: id} }
+func noopGen(_ *rand.Rand, id cqrs.Identifier) cqrs.Command      { return noop{ID: id} }
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it passes if the invariants always hold", func(t *testing.T) {
+		t.Parallel()
+
+		rt := &recordingT{}
+
+		proptest.Check(rt, proptest.Property[*counter]{
+			New:        newCounter,
+			Commands:   []proptest.Generator{incrementGen, noopGen},
+			Invariants: []proptest.Invariant[*counter]{withinLimit},
+		}, proptest.WithSeed(1))
+
+		assert.Empty(t, rt.failures)
+	})
+
+	t.Run("it shrinks the failing sequence to a minimal one", func(t *testing.T) {
+		t.Parallel()
+
+		rt := &recordingT{}
+
+		proptest.Check(rt, proptest.Property[*counter]{
+			New: newCounter,
+			Commands: []proptest.Generator{
+				incrementGen,
+				noopGen,
+				func(_ *rand.Rand, id cqrs.Identifier) cqrs.Command { return incrementTwice{ID: id} },
+			},
+			Invariants: []proptest.Invariant[*counter]{withinLimit},
+		}, proptest.WithSeed(1), proptest.WithMaxCommands(30))
+
+		assert.Len(t, rt.failures, 1)
+		assert.Contains(t, rt.failures[0], "seed 1")
+		assert.Contains(t, rt.failures[0], "count 4 is over the limit 3")
+		assert.NotContains(t, rt.failures[0], "noop")
+		assert.Contains(t, rt.failures[0], "#1 proptest_test.incrementTwice")
+		assert.NotContains(t, rt.failures[0], "#2")
+	})
+
+	t.Run("it reports a state which differs after replay", func(t *testing.T) {
+		t.Parallel()
+
+		rt := &recordingT{}
+
+		proptest.Check(rt, proptest.Property[*forgetfulCounter]{
+			New: func(id cqrs.Identifier) *forgetfulCounter {
+				return &forgetfulCounter{counter: counter{id: id, limit: 3}}
+			},
+			Commands: []proptest.Generator{incrementGen},
+		}, proptest.WithSeed(1))
+
+		assert.Len(t, rt.failures, 1)
+		assert.Contains(t, rt.failures[0], "replayed state differs")
+		assert.Contains(t, rt.failures[0], "#0 proptest_test.increment")
+		assert.NotContains(t, rt.failures[0], "#1")
+	})
+
+	t.Run("it panics if the property is incomplete", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Panics(t, func() {
+			proptest.Check(&recordingT{}, proptest.Property[*counter]{Commands: []proptest.Generator{noopGen}})
+		})
+		assert.Panics(t, func() {
+			proptest.Check(&recordingT{}, proptest.Property[*counter]{New: newCounter})
+		})
+	})
+}
+
+// forgetfulCounter changes its state in the command handler, so the state is lost on replay.
+type forgetfulCounter struct {
+	counter
+
+	handled int
+}
+
+func (c *forgetfulCounter) Increment(cmd increment) ([]aggtest.Event, error) {
+	c.handled++
+
+	return c.counter.Increment(cmd)
+}
@@ -0,0 +1,76 @@
+package specdoc
+
+import (
+	htmltemplate "html/template"
+	"io"
+	"text/template"
+)
+
+const markdownTemplate = `# Specification
+{{range .}}
+## {{.Aggregate}}
+{{range .Scenarios}}
+### {{.Name}}{{if .Failed}} (failing){{end}}
+
+**Given**
+{{range .Given}}
+- ` + "`{{describeEvent .}}`" + `{{else}}
+- nothing has happened yet{{end}}
+
+**When**
+{{range .When}}
+- ` + "`{{describeCommand .}}`" + `{{end}}
+
+**Then**
+{{if .Err}}
+- it fails with ` + "`{{.Err}}`" + `{{else}}{{range .Then}}
+- ` + "`{{describeEvent .}}`" + `{{else}}
+- nothing happens{{end}}{{end}}
+{{end}}{{end}}`
+
+const htmlTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Specification</title>
+</head>
+<body>
+<h1>Specification</h1>
+{{range .}}<section>
+<h2>{{.Aggregate}}</h2>
+{{range .Scenarios}}<article>
+<h3>{{.Name}}{{if .Failed}} (failing){{end}}</h3>
+<h4>Given</h4>
+<ul>{{range .Given}}<li><code>{{describeEvent .}}</code></li>{{else}}<li>nothing has happened yet</li>{{end}}</ul>
+<h4>When</h4>
+<ul>{{range .When}}<li><code>{{describeCommand .}}</code></li>{{end}}</ul>
+<h4>Then</h4>
+<ul>{{if .Err}}<li>it fails with <code>{{.Err}}</code></li>{{else}}{{range .Then}}<li><code>{{describeEvent .}}</code></li>{{else}}<li>nothing happens</li>{{end}}{{end}}</ul>
+</article>
+{{end}}</section>
+{{end}}</body>
+</html>
+`
+
+//nolint:gochecknoglobals
+var (
+	markdown = template.Must(template.New("markdown").Funcs(template.FuncMap{
+		"describeEvent":   DescribeEvent,
+		"describeCommand": DescribeCommand,
+	}).Parse(markdownTemplate))
+
+	html = htmltemplate.Must(htmltemplate.New("html").Funcs(htmltemplate.FuncMap{
+		"describeEvent":   DescribeEvent,
+		"describeCommand": DescribeCommand,
+	}).Parse(htmlTemplate))
+)
+
+// WriteMarkdown renders the recorded scenarios as Markdown.
+func (r *Reporter) WriteMarkdown(w io.Writer) error {
+	return markdown.Execute(w, r.Features())
+}
+
+// WriteHTML renders the recorded scenarios as an HTML page.
+func (r *Reporter) WriteHTML(w io.Writer) error {
+	return html.Execute(w, r.Features())
+}
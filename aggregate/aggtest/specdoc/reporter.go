@@ -0,0 +1,177 @@
+// Package specdoc turns the executed Given/When/Then scenarios into living documentation.
+//
+// The testdsl packages record the scenarios into a Reporter when it is given with their WithReporter option.
+// Once the tests are run the reporter renders the scenarios as Markdown or HTML grouped by aggregate,
+// so that the behaviour of the aggregates can be read without reading Go.
+package specdoc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/screwyprof/cqrs"
+)
+
+// ErrUnknownFormat is returned when the documentation format cannot be told from the file extension.
+var ErrUnknownFormat = errors.New("unknown documentation format")
+
+// Scenario is an executed Given/When/Then scenario.
+type Scenario struct {
+	// Aggregate is the type of the aggregate the commands are addressed to.
+	Aggregate string
+	// Name describes the scenario, it is derived from the test name.
+	Name string
+
+	Given []cqrs.DomainEvent
+	When  []cqrs.Command
+
+	// Then holds the produced events unless the scenario ends with an error.
+	Then []cqrs.DomainEvent
+	Err  error
+
+	// Failed reports whether the assertions of the scenario failed.
+	Failed bool
+}
+
+// Feature groups the scenarios of an aggregate.
+type Feature struct {
+	Aggregate string
+	Scenarios []Scenario
+}
+
+// Reporter collects the executed scenarios.
+//
+// It is safe for concurrent use, so the scenarios of the parallel tests can be recorded.
+type Reporter struct {
+	scenarios []Scenario
+	mu        sync.Mutex
+}
+
+// NewReporter creates a new instance of Reporter.
+func NewReporter() *Reporter {
+	return &Reporter{}
+}
+
+// Record records the executed scenario.
+func (r *Reporter) Record(s Scenario) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.scenarios = append(r.scenarios, s)
+}
+
+// Features returns the recorded scenarios grouped by aggregate.
+//
+// Both the aggregates and their scenarios are sorted by name, so the documentation does not depend
+// on the order the tests are run in.
+func (r *Reporter) Features() []Feature {
+	r.mu.Lock()
+	scenarios := slices.Clone(r.scenarios)
+	r.mu.Unlock()
+
+	slices.SortStableFunc(scenarios, func(a, b Scenario) int {
+		if c := strings.Compare(a.Aggregate, b.Aggregate); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	var features []Feature
+	for _, s := range scenarios {
+		if len(features) == 0 || features[len(features)-1].Aggregate != s.Aggregate {
+			features = append(features, Feature{Aggregate: s.Aggregate})
+		}
+
+		last := &features[len(features)-1]
+		last.Scenarios = append(last.Scenarios, s)
+	}
+
+	return features
+}
+
+// WriteFile writes the documentation to the given file.
+//
+// The format is chosen by the file extension: ".md" for Markdown, ".html" or ".htm" for HTML.
+func (r *Reporter) WriteFile(path string) error {
+	write, err := r.writerFor(path)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := write(f); err != nil {
+		_ = f.Close()
+
+		return err
+	}
+
+	return f.Close()
+}
+
+func (r *Reporter) writerFor(path string) (func(w io.Writer) error, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".md":
+		return r.WriteMarkdown, nil
+	case ".html", ".htm":
+		return r.WriteHTML, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownFormat, path)
+	}
+}
+
+// ScenarioName derives a scenario name from the name of the test which runs it.
+//
+// For example "TestAggregate/opens_an_account" becomes "opens an account".
+func ScenarioName(testName string) string {
+	if idx := strings.LastIndex(testName, "/"); idx >= 0 {
+		testName = testName[idx+1:]
+	}
+
+	return strings.ReplaceAll(testName, "_", " ")
+}
+
+// DescribeEvent describes an event by its type and data, e.g. "MoneyDeposited {Amount: 500, Balance: 500}".
+func DescribeEvent(e cqrs.DomainEvent) string {
+	return describe(e.EventType(), e)
+}
+
+// DescribeCommand describes a command by its type and data, e.g. "DepositMoney {Amount: 500}".
+func DescribeCommand(c cqrs.Command) string {
+	return describe(c.CommandType(), c)
+}
+
+func describe(messageType string, message any) string {
+	v := reflect.ValueOf(message)
+	if v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return fmt.Sprintf("%s {%v}", messageType, message)
+	}
+
+	fields := make([]string, 0, v.NumField())
+	for i := range v.NumField() {
+		if field := v.Type().Field(i); field.IsExported() {
+			fields = append(fields, fmt.Sprintf("%s: %v", field.Name, v.Field(i).Interface()))
+		}
+	}
+
+	if len(fields) == 0 {
+		return messageType
+	}
+
+	return fmt.Sprintf("%s {%s}", messageType, strings.Join(fields, ", "))
+}
@@ -0,0 +1,139 @@
+package specdoc_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/cqrs"
+	"github.com/screwyprof/cqrs/aggregate/aggtest"
+	"github.com/screwyprof/cqrs/aggregate/aggtest/specdoc"
+)
+
+var errCannotHappen = errors.New("it cannot happen <twice>")
+
+func TestReporter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("groups the scenarios by aggregate sorted by name", func(t *testing.T) {
+		t.Parallel()
+
+		r := specdoc.NewReporter()
+		r.Record(specdoc.Scenario{Aggregate: "b.Aggregate", Name: "second"})
+		r.Record(specdoc.Scenario{Aggregate: "a.Aggregate", Name: "only"})
+		r.Record(specdoc.Scenario{Aggregate: "b.Aggregate", Name: "first"})
+
+		features := r.Features()
+
+		assert.Len(t, features, 2)
+		assert.Equal(t, "a.Aggregate", features[0].Aggregate)
+		assert.Equal(t, []string{"only"}, scenarioNames(features[0]))
+		assert.Equal(t, "b.Aggregate", features[1].Aggregate)
+		assert.Equal(t, []string{"first", "second"}, scenarioNames(features[1]))
+	})
+
+	t.Run("renders the scenarios as markdown", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		assert.NoError(t, createReporter().WriteMarkdown(&buf))
+
+		doc := buf.String()
+		assert.Contains(t, doc, "## aggtest.TestAggregate")
+		assert.Contains(t, doc, "### something happens")
+		assert.Contains(t, doc, "- nothing has happened yet")
+		assert.Contains(t, doc, "- `MakeSomethingHappen {AggID: ID}`")
+		assert.Contains(t, doc, "- `SomethingHappened {Data: happened once}`")
+		assert.Contains(t, doc, "### something cannot happen twice (failing)")
+		assert.Contains(t, doc, "- it fails with `it cannot happen <twice>`")
+	})
+
+	t.Run("renders the scenarios as html", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		assert.NoError(t, createReporter().WriteHTML(&buf))
+
+		doc := buf.String()
+		assert.Contains(t, doc, "<h2>aggtest.TestAggregate</h2>")
+		assert.Contains(t, doc, "<code>SomethingHappened {Data: happened once}</code>")
+		assert.Contains(t, doc, "<code>it cannot happen &lt;twice&gt;</code>")
+	})
+
+	t.Run("writes the file in the format given by its extension", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		r := createReporter()
+
+		assert.NoError(t, r.WriteFile(filepath.Join(dir, "spec.md")))
+		assert.NoError(t, r.WriteFile(filepath.Join(dir, "spec.html")))
+
+		md, err := os.ReadFile(filepath.Join(dir, "spec.md"))
+		assert.NoError(t, err)
+		assert.Contains(t, string(md), "# Specification")
+
+		html, err := os.ReadFile(filepath.Join(dir, "spec.html"))
+		assert.NoError(t, err)
+		assert.Contains(t, string(html), "<h1>Specification</h1>")
+	})
+
+	t.Run("cannot write a file of unknown format", func(t *testing.T) {
+		t.Parallel()
+
+		err := createReporter().WriteFile(filepath.Join(t.TempDir(), "spec.txt"))
+
+		assert.ErrorIs(t, err, specdoc.ErrUnknownFormat)
+	})
+}
+
+func TestScenarioName(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "opens an account", specdoc.ScenarioName("TestAggregate/opens_an_account"))
+	assert.Equal(t, "TestAggregate", specdoc.ScenarioName("TestAggregate"))
+}
+
+func TestDescribeEvent(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "SomethingHappened {Data: two words}", specdoc.DescribeEvent(aggtest.SomethingHappened{Data: "two words"}))
+	assert.Equal(t, "SomethingElseHappened", specdoc.DescribeEvent(aggtest.SomethingElseHappened{}))
+}
+
+func createReporter() *specdoc.Reporter {
+	ID := aggtest.StringIdentifier("ID")
+
+	r := specdoc.NewReporter()
+	r.Record(specdoc.Scenario{
+		Aggregate: "aggtest.TestAggregate",
+		Name:      "something happens",
+		When:      []cqrs.Command{aggtest.MakeSomethingHappen{AggID: ID}},
+		Then:      []cqrs.DomainEvent{aggtest.SomethingHappened{Data: "happened once"}},
+	})
+	r.Record(specdoc.Scenario{
+		Aggregate: "aggtest.TestAggregate",
+		Name:      "something cannot happen twice",
+		Given:     []cqrs.DomainEvent{aggtest.SomethingHappened{Data: "happened once"}},
+		When:      []cqrs.Command{aggtest.MakeSomethingHappen{AggID: ID}},
+		Err:       errCannotHappen,
+		Failed:    true,
+	})
+
+	return r
+}
+
+func scenarioNames(f specdoc.Feature) []string {
+	names := make([]string, 0, len(f.Scenarios))
+	for _, s := range f.Scenarios {
+		names = append(names, s.Name)
+	}
+
+	return names
+}
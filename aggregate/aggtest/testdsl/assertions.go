@@ -0,0 +1,239 @@
+package testdsl
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/cqrs"
+)
+
+// EventMatcher checks a single event, it returns an error describing the mismatch.
+type EventMatcher func(e cqrs.DomainEvent) error
+
+// EventOf returns an EventMatcher which checks that the event is of type E and satisfies the predicate.
+//
+// The predicate may be nil to check the type only.
+func EventOf[E cqrs.DomainEvent](predicate func(E) error) EventMatcher {
+	return func(e cqrs.DomainEvent) error {
+		event, ok := e.(E)
+		if !ok {
+			var want E
+
+			return fmt.Errorf("expected %T, got %T", want, e)
+		}
+
+		if predicate == nil {
+			return nil
+		}
+
+		return predicate(event)
+	}
+}
+
+// ThenNoEvents asserts that the command succeeds without producing any events.
+func ThenNoEvents() ThenFn {
+	return thenNoEventsAssertion().then()
+}
+
+func thenNoEventsAssertion() assertion {
+	return func(t TestingT) Checker {
+		t.Helper()
+
+		return func(got []cqrs.DomainEvent, err error) {
+			t.Helper()
+
+			if assert.NoError(t, err) && len(got) != 0 {
+				t.Errorf("expected no events, got %d:\n%s", len(got), describeEvents(got))
+			}
+		}
+	}
+}
+
+// ThenContains asserts that the expected events are among the produced ones, in any order.
+func ThenContains(want ...cqrs.DomainEvent) ThenFn {
+	return thenContainsAssertion(want...).then()
+}
+
+func thenContainsAssertion(want ...cqrs.DomainEvent) assertion {
+	return func(t TestingT) Checker {
+		t.Helper()
+
+		return func(got []cqrs.DomainEvent, err error) {
+			t.Helper()
+
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			remaining := slices.Clone(got)
+			for _, w := range want {
+				idx := slices.IndexFunc(remaining, func(e cqrs.DomainEvent) bool {
+					return assert.ObjectsAreEqual(w, e)
+				})
+
+				if idx < 0 {
+					t.Errorf("expected event %#v is not produced, got:\n%s", w, describeEvents(got))
+
+					continue
+				}
+
+				remaining = slices.Delete(remaining, idx, idx+1)
+			}
+		}
+	}
+}
+
+// ThenMatches asserts that the produced events satisfy the given check.
+func ThenMatches(check func(events []cqrs.DomainEvent) error) ThenFn {
+	return thenMatchesAssertion(check).then()
+}
+
+func thenMatchesAssertion(check func(events []cqrs.DomainEvent) error) assertion {
+	return func(t TestingT) Checker {
+		t.Helper()
+
+		return func(got []cqrs.DomainEvent, err error) {
+			t.Helper()
+
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			if err := check(got); err != nil {
+				t.Errorf("events do not match: %v\n%s", err, describeEvents(got))
+			}
+		}
+	}
+}
+
+// ThenEvents asserts that the produced events match the matchers one by one.
+//
+// Example:
+//
+//	ThenEvents(
+//		EventOf(func(e testdata.TestEvent) error { return nil }),
+//	)
+func ThenEvents(matchers ...EventMatcher) ThenFn {
+	return thenEventsAssertion(matchers...).then()
+}
+
+func thenEventsAssertion(matchers ...EventMatcher) assertion {
+	return func(t TestingT) Checker {
+		t.Helper()
+
+		return func(got []cqrs.DomainEvent, err error) {
+			t.Helper()
+
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			if len(got) != len(matchers) {
+				t.Errorf("expected %d events, got %d:\n%s", len(matchers), len(got), describeEvents(got))
+
+				return
+			}
+
+			for i, match := range matchers {
+				if err := match(got[i]); err != nil {
+					t.Errorf("event #%d (%s) does not match: %v", i, got[i].EventType(), err)
+				}
+			}
+		}
+	}
+}
+
+// ThenIgnoringFields asserts that exactly the expected events are produced
+// comparing all but the given fields, e.g. generated identifiers or timestamps.
+//
+// Only the exported fields of the events can be ignored.
+func ThenIgnoringFields(fields []string, want ...cqrs.DomainEvent) ThenFn {
+	return thenIgnoringFieldsAssertion(fields, want...).then()
+}
+
+func thenIgnoringFieldsAssertion(fields []string, want ...cqrs.DomainEvent) assertion {
+	return func(t TestingT) Checker {
+		t.Helper()
+
+		return func(got []cqrs.DomainEvent, err error) {
+			t.Helper()
+
+			if assert.NoError(t, err) {
+				assertEvents(t, withoutFields(want, fields), withoutFields(got, fields))
+			}
+		}
+	}
+}
+
+// assertEvents compares the events one by one, so that the failure points to the event which differs.
+func assertEvents(t TestingT, want, got []cqrs.DomainEvent) {
+	t.Helper()
+
+	if len(want) != len(got) {
+		t.Errorf("expected %d events, got %d:\nexpected:\n%s\nactual:\n%s",
+			len(want), len(got), describeEvents(want), describeEvents(got))
+
+		return
+	}
+
+	for i := range want {
+		assert.Equal(t, want[i], got[i], "event #%d (%s) differs", i, want[i].EventType())
+	}
+}
+
+func describeEvents(events []cqrs.DomainEvent) string {
+	if len(events) == 0 {
+		return "\t(none)"
+	}
+
+	var description string
+	for i, e := range events {
+		description += fmt.Sprintf("\t#%d %#v\n", i, e)
+	}
+
+	return description
+}
+
+func withoutFields(events []cqrs.DomainEvent, fields []string) []cqrs.DomainEvent {
+	cleared := make([]cqrs.DomainEvent, 0, len(events))
+	for _, e := range events {
+		cleared = append(cleared, withoutEventFields(e, fields))
+	}
+
+	return cleared
+}
+
+func withoutEventFields(e cqrs.DomainEvent, fields []string) cqrs.DomainEvent {
+	v := reflect.ValueOf(e)
+
+	isPointer := v.Kind() == reflect.Pointer
+	if isPointer {
+		if v.IsNil() {
+			return e
+		}
+
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return e
+	}
+
+	copied := reflect.New(v.Type())
+	copied.Elem().Set(v)
+
+	for _, name := range fields {
+		if field := copied.Elem().FieldByName(name); field.IsValid() && field.CanSet() {
+			field.SetZero()
+		}
+	}
+
+	if isPointer {
+		return copied.Interface().(cqrs.DomainEvent) //nolint:forcetypeassert
+	}
+
+	return copied.Elem().Interface().(cqrs.DomainEvent) //nolint:forcetypeassert
+}
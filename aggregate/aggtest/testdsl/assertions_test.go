@@ -0,0 +1,236 @@
+package testdsl_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/cqrs"
+	"github.com/screwyprof/cqrs/aggregate/aggtest"
+	. "github.com/screwyprof/cqrs/aggregate/aggtest/testdsl"
+)
+
+var errNoData = errors.New("no data")
+
+// recordingT records the failures instead of failing the test.
+type recordingT struct {
+	failures []string
+}
+
+func (t *recordingT) Errorf(format string, args ...any) {
+	t.failures = append(t.failures, fmt.Sprintf(format, args...))
+}
+
+func (t *recordingT) Helper() {}
+
+func check(then func(t TestingT) Checker, got []cqrs.DomainEvent, err error) []string {
+	t := &recordingT{}
+	then(t)(got, err)
+
+	return t.failures
+}
+
+func TestThen(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it passes if exactly the expected events are produced", func(t *testing.T) {
+		t.Parallel()
+
+		failures := check(
+			ThenAssertion(aggtest.SomethingHappened{Data: "a"}),
+			events(aggtest.SomethingHappened{Data: "a"}),
+			nil,
+		)
+
+		assert.Empty(t, failures)
+	})
+
+	t.Run("it reports which event differs", func(t *testing.T) {
+		t.Parallel()
+
+		failures := check(
+			ThenAssertion(aggtest.SomethingElseHappened{}, aggtest.SomethingHappened{Data: "a"}),
+			events(aggtest.SomethingElseHappened{}, aggtest.SomethingHappened{Data: "b"}),
+			nil,
+		)
+
+		assert.Len(t, failures, 1)
+		assert.Contains(t, failures[0], "event #1 (SomethingHappened) differs")
+	})
+
+	t.Run("it reports the events if their number differs", func(t *testing.T) {
+		t.Parallel()
+
+		failures := check(ThenAssertion(aggtest.SomethingHappened{}), nil, nil)
+
+		assert.Len(t, failures, 1)
+		assert.Contains(t, failures[0], "expected 1 events, got 0")
+	})
+}
+
+func TestThenNoEvents(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it passes if no events are produced", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Empty(t, check(ThenNoEventsAssertion(), nil, nil))
+	})
+
+	t.Run("it fails if any events are produced", func(t *testing.T) {
+		t.Parallel()
+
+		failures := check(ThenNoEventsAssertion(), events(aggtest.SomethingHappened{}), nil)
+
+		assert.Len(t, failures, 1)
+		assert.Contains(t, failures[0], "expected no events, got 1")
+	})
+
+	t.Run("it fails if the command fails", func(t *testing.T) {
+		t.Parallel()
+
+		assert.NotEmpty(t, check(ThenNoEventsAssertion(), nil, aggtest.ErrItCanHappenOnceOnly))
+	})
+}
+
+func TestThenContains(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it passes if the expected events are produced in any order", func(t *testing.T) {
+		t.Parallel()
+
+		failures := check(
+			ThenContainsAssertion(aggtest.SomethingHappened{Data: "a"}, aggtest.SomethingElseHappened{}),
+			events(aggtest.SomethingElseHappened{}, aggtest.SomethingHappened{Data: "b"}, aggtest.SomethingHappened{Data: "a"}),
+			nil,
+		)
+
+		assert.Empty(t, failures)
+	})
+
+	t.Run("it fails if an expected event is missing", func(t *testing.T) {
+		t.Parallel()
+
+		failures := check(
+			ThenContainsAssertion(aggtest.SomethingHappened{Data: "a"}, aggtest.SomethingHappened{Data: "a"}),
+			events(aggtest.SomethingHappened{Data: "a"}),
+			nil,
+		)
+
+		assert.Len(t, failures, 1)
+		assert.Contains(t, failures[0], "is not produced")
+	})
+}
+
+func TestThenMatches(t *testing.T) {
+	t.Parallel()
+
+	hasTwoEvents := func(events []cqrs.DomainEvent) error {
+		if len(events) != 2 {
+			return fmt.Errorf("expected two events")
+		}
+
+		return nil
+	}
+
+	t.Run("it passes if the check succeeds", func(t *testing.T) {
+		t.Parallel()
+
+		failures := check(
+			ThenMatchesAssertion(hasTwoEvents),
+			events(aggtest.SomethingHappened{}, aggtest.SomethingHappened{}),
+			nil,
+		)
+
+		assert.Empty(t, failures)
+	})
+
+	t.Run("it reports the error of the check", func(t *testing.T) {
+		t.Parallel()
+
+		failures := check(ThenMatchesAssertion(hasTwoEvents), events(aggtest.SomethingHappened{}), nil)
+
+		assert.Len(t, failures, 1)
+		assert.Contains(t, failures[0], "expected two events")
+	})
+}
+
+func TestThenEvents(t *testing.T) {
+	t.Parallel()
+
+	hasData := EventOf(func(e aggtest.SomethingHappened) error {
+		if e.Data == "" {
+			return errNoData
+		}
+
+		return nil
+	})
+
+	t.Run("it passes if each event matches", func(t *testing.T) {
+		t.Parallel()
+
+		failures := check(
+			ThenEventsAssertion(hasData, EventOf[aggtest.SomethingElseHappened](nil)),
+			events(aggtest.SomethingHappened{Data: "a"}, aggtest.SomethingElseHappened{}),
+			nil,
+		)
+
+		assert.Empty(t, failures)
+	})
+
+	t.Run("it reports the event which does not satisfy the predicate", func(t *testing.T) {
+		t.Parallel()
+
+		failures := check(ThenEventsAssertion(hasData), events(aggtest.SomethingHappened{}), nil)
+
+		assert.Len(t, failures, 1)
+		assert.Contains(t, failures[0], "event #0 (SomethingHappened) does not match: no data")
+	})
+
+	t.Run("it reports the event of another type", func(t *testing.T) {
+		t.Parallel()
+
+		failures := check(ThenEventsAssertion(hasData), events(aggtest.SomethingElseHappened{}), nil)
+
+		assert.Len(t, failures, 1)
+		assert.Contains(t, failures[0], "expected aggtest.SomethingHappened, got aggtest.SomethingElseHappened")
+	})
+}
+
+func TestThenIgnoringFields(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it ignores the given fields", func(t *testing.T) {
+		t.Parallel()
+
+		failures := check(
+			ThenIgnoringFieldsAssertion(
+				[]string{"Data"},
+				aggtest.SomethingHappened{Data: "generated"},
+				&aggtest.SomethingHappened{},
+			),
+			events(aggtest.SomethingHappened{Data: "a"}, &aggtest.SomethingHappened{Data: "b"}),
+			nil,
+		)
+
+		assert.Empty(t, failures)
+	})
+
+	t.Run("it compares the other fields", func(t *testing.T) {
+		t.Parallel()
+
+		failures := check(
+			ThenIgnoringFieldsAssertion([]string{"Version"}, aggtest.SomethingHappened{Data: "a"}),
+			events(aggtest.SomethingHappened{Data: "b"}),
+			nil,
+		)
+
+		assert.Len(t, failures, 1)
+	})
+}
+
+func events(e ...cqrs.DomainEvent) []cqrs.DomainEvent {
+	return e
+}
@@ -0,0 +1,12 @@
+package testdsl
+
+// The assertions are exported to the tests, so that their failures can be recorded instead of failing the test.
+var (
+	ThenAssertion               = thenAssertion
+	ThenNoEventsAssertion       = thenNoEventsAssertion
+	ThenContainsAssertion       = thenContainsAssertion
+	ThenMatchesAssertion        = thenMatchesAssertion
+	ThenEventsAssertion         = thenEventsAssertion
+	ThenIgnoringFieldsAssertion = thenIgnoringFieldsAssertion
+	ThenGoldenAssertion         = thenGoldenAssertion
+)
@@ -0,0 +1,149 @@
+package testdsl
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/cqrs"
+)
+
+const (
+	// GoldenDir is the directory the golden files are kept in, relative to the package under test.
+	GoldenDir = "testdata"
+
+	// UpdateGoldenFlag is the flag which makes the golden assertions record the events instead of comparing them.
+	//
+	// It is registered unless another package has already defined a flag with the same name,
+	// the flag is looked up when the assertion runs, so the one defined by the other package is honoured then.
+	UpdateGoldenFlag = "update"
+
+	// UpdateGoldenEnv is the environment variable which requests the update as well when it is set
+	// to a true value, e.g. UPDATE_GOLDEN=1, it is a fallback for the test binaries run without flags.
+	UpdateGoldenEnv = "UPDATE_GOLDEN"
+)
+
+func init() { //nolint:gochecknoinits
+	if flag.Lookup(UpdateGoldenFlag) == nil {
+		flag.Bool(UpdateGoldenFlag, false, "update the golden files")
+	}
+}
+
+// goldenEvent is the way an event is recorded in a golden file.
+type goldenEvent struct {
+	Type string           `json:"type"`
+	Data cqrs.DomainEvent `json:"data"`
+}
+
+// ThenGolden asserts that the produced events match the ones recorded in the golden file with the given name.
+//
+// The events are recorded as JSON along with their EventType(), so any change to the event schema,
+// e.g. a renamed field or event type, makes the assertion fail. Run the tests with -update
+// to record the events again once the change is intended:
+//
+//	go test ./... -run TestAggregate -update
+//
+// UPDATE_GOLDEN=1 does the same where the flag cannot be passed.
+func ThenGolden(name string) ThenFn {
+	return thenGoldenAssertion(name).then()
+}
+
+func thenGoldenAssertion(name string) assertion {
+	return func(t TestingT) Checker {
+		t.Helper()
+
+		return func(got []cqrs.DomainEvent, err error) {
+			t.Helper()
+
+			if assert.NoError(t, err) {
+				AssertGolden(t, name, got)
+			}
+		}
+	}
+}
+
+// AssertGolden asserts that the events match the ones recorded in the golden file with the given name.
+//
+// The file is GoldenDir/<name>.golden.json, it is (re)written instead when the tests run with -update.
+func AssertGolden(t TestingT, name string, events []cqrs.DomainEvent) {
+	t.Helper()
+
+	got, err := marshalGolden(events)
+	if err != nil {
+		t.Errorf("cannot record the events: %v", err)
+
+		return
+	}
+
+	path := GoldenPath(name)
+
+	if updateGolden() {
+		if err := writeGolden(path, got); err != nil {
+			t.Errorf("cannot update golden file %s: %v", path, err)
+		}
+
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("golden file %s does not exist, run the tests with -update to record it", path)
+
+		return
+	}
+
+	if err != nil {
+		t.Errorf("cannot read golden file %s: %v", path, err)
+
+		return
+	}
+
+	assert.Equal(t, string(want), string(got),
+		"events differ from golden file %s, run the tests with -update if the change is intended", path)
+}
+
+// updateGolden tells whether the golden files are to be recorded, see UpdateGoldenFlag and UpdateGoldenEnv.
+func updateGolden() bool {
+	if f := flag.Lookup(UpdateGoldenFlag); f != nil {
+		if update, err := strconv.ParseBool(f.Value.String()); err == nil && update {
+			return true
+		}
+	}
+
+	update, err := strconv.ParseBool(os.Getenv(UpdateGoldenEnv))
+
+	return err == nil && update
+}
+
+// GoldenPath returns the path of the golden file with the given name.
+func GoldenPath(name string) string {
+	return filepath.Join(GoldenDir, filepath.FromSlash(name)+".golden.json")
+}
+
+func marshalGolden(events []cqrs.DomainEvent) ([]byte, error) {
+	recorded := make([]goldenEvent, 0, len(events))
+	for _, e := range events {
+		recorded = append(recorded, goldenEvent{Type: e.EventType(), Data: e})
+	}
+
+	data, err := json.MarshalIndent(recorded, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(data, '\n'), nil
+}
+
+func writeGolden(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
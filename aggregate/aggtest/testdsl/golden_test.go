@@ -0,0 +1,105 @@
+package testdsl_test
+
+import (
+	"flag"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/cqrs/aggregate/aggtest"
+	. "github.com/screwyprof/cqrs/aggregate/aggtest/testdsl"
+)
+
+func TestThenGolden(t *testing.T) {
+	t.Run("it updates the golden file if the update is requested", func(t *testing.T) {
+		t.Chdir(t.TempDir())
+		t.Setenv(UpdateGoldenEnv, "1")
+
+		failures := check(
+			ThenGoldenAssertion("scenario/something_happened"),
+			events(aggtest.SomethingHappened{Data: "a"}),
+			nil,
+		)
+
+		assert.Empty(t, failures)
+
+		recorded, err := os.ReadFile(GoldenPath("scenario/something_happened"))
+		assert.NoError(t, err)
+		assert.JSONEq(t, `[{"type":"SomethingHappened","data":{"Data":"a"}}]`, string(recorded))
+	})
+
+	t.Run("it updates the golden file if the update flag is set", func(t *testing.T) {
+		t.Chdir(t.TempDir())
+		setUpdateFlag(t)
+
+		failures := check(
+			ThenGoldenAssertion("something_happened"),
+			events(aggtest.SomethingHappened{Data: "a"}),
+			nil,
+		)
+
+		assert.Empty(t, failures)
+
+		recorded, err := os.ReadFile(GoldenPath("something_happened"))
+		assert.NoError(t, err)
+		assert.JSONEq(t, `[{"type":"SomethingHappened","data":{"Data":"a"}}]`, string(recorded))
+	})
+
+	t.Run("it passes if the events match the golden file", func(t *testing.T) {
+		failures := check(
+			ThenGoldenAssertion("something_happened"),
+			events(aggtest.SomethingHappened{Data: "a"}, aggtest.SomethingElseHappened{}),
+			nil,
+		)
+
+		assert.Empty(t, failures)
+	})
+
+	t.Run("it reports the events which differ from the golden file", func(t *testing.T) {
+		failures := check(
+			ThenGoldenAssertion("something_happened"),
+			events(aggtest.SomethingHappened{Data: "b"}, aggtest.SomethingElseHappened{}),
+			nil,
+		)
+
+		assert.Len(t, failures, 1)
+		assert.Contains(t, failures[0], "events differ from golden file")
+	})
+
+	t.Run("it reports the changed event type", func(t *testing.T) {
+		failures := check(
+			ThenGoldenAssertion("something_happened"),
+			events(aggtest.SomethingHappenedV2{Data: "a"}, aggtest.SomethingElseHappened{}),
+			nil,
+		)
+
+		assert.Len(t, failures, 1)
+		assert.Contains(t, failures[0], "something-happened.v2")
+	})
+
+	t.Run("it reports a missing golden file", func(t *testing.T) {
+		failures := check(ThenGoldenAssertion("unknown"), events(aggtest.SomethingHappened{}), nil)
+
+		assert.Len(t, failures, 1)
+		assert.Contains(t, failures[0], "does not exist")
+	})
+
+	t.Run("it reports an unexpected error", func(t *testing.T) {
+		failures := check(ThenGoldenAssertion("something_happened"), nil, errNoData)
+
+		assert.NotEmpty(t, failures)
+	})
+}
+
+func setUpdateFlag(t *testing.T) {
+	t.Helper()
+
+	if err := flag.Set(UpdateGoldenFlag, "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		_ = flag.Set(UpdateGoldenFlag, "false")
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/screwyprof/cqrs"
+	"github.com/screwyprof/cqrs/aggregate/aggtest/specdoc"
 )
 
 // GivenFn is a test init function.
@@ -17,12 +18,44 @@ type WhenFn func(agg cqrs.ESAggregate, err error) ([]cqrs.DomainEvent, error)
 // ThenFn prepares the Checker.
 type ThenFn func(t *testing.T) Checker
 
+// TestingT is the part of *testing.T the assertions use.
+type TestingT interface {
+	Errorf(format string, args ...any)
+	Helper()
+}
+
+// assertion prepares the Checker which reports the failures to TestingT.
+type assertion func(t TestingT) Checker
+
+// then adapts the assertion to ThenFn.
+func (a assertion) then() ThenFn {
+	return func(t *testing.T) Checker {
+		t.Helper()
+
+		return a(t)
+	}
+}
+
 // Checker asserts the given results.
 type Checker func(got []cqrs.DomainEvent, err error)
 
 // AggregateTester defines an aggregate tester.
 type AggregateTester func(given GivenFn, when WhenFn, then ThenFn)
 
+// Option configures the aggregate tester.
+type Option func(*tester)
+
+type tester struct {
+	reporter *specdoc.Reporter
+}
+
+// WithReporter records the executed scenarios into the given reporter to produce living documentation.
+func WithReporter(reporter *specdoc.Reporter) Option {
+	return func(t *tester) {
+		t.reporter = reporter
+	}
+}
+
 // Test runs the test.
 //
 // Example:
@@ -32,13 +65,38 @@ type AggregateTester func(given GivenFn, when WhenFn, then ThenFn)
 //		  When(testdata.TestCommand{Param: "param"}),
 //		  Then(testdata.TestEvent{Data: "param"}),
 //	 )
-func Test(t *testing.T) AggregateTester { //nolint:tparallel,paralleltest
+func Test(t *testing.T, opts ...Option) AggregateTester { //nolint:tparallel,paralleltest
 	t.Helper()
 
+	config := &tester{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
 	return func(given GivenFn, when WhenFn, then ThenFn) {
 		t.Helper()
 
-		then(t)(when(applyEvents(given)))
+		if config.reporter == nil {
+			then(t)(when(applyEvents(given)))
+
+			return
+		}
+
+		agg, events := given()
+		recorder := &commandRecorder{ESAggregate: agg}
+
+		got, err := when(applyEvents(Given(recorder, events...)))
+		then(t)(got, err)
+
+		config.reporter.Record(specdoc.Scenario{
+			Aggregate: agg.AggregateType(),
+			Name:      specdoc.ScenarioName(t.Name()),
+			Given:     events,
+			When:      recorder.commands,
+			Then:      got,
+			Err:       err,
+			Failed:    t.Failed(),
+		})
 	}
 }
 
@@ -60,23 +118,34 @@ func When(c cqrs.Command) WhenFn {
 	}
 }
 
-// Then asserts that the expected events are applied.
+// Then asserts that exactly the expected events are produced.
+//
+// On failure it reports which event differs and how.
 func Then(want ...cqrs.DomainEvent) ThenFn {
-	return func(t *testing.T) Checker {
+	return thenAssertion(want...).then()
+}
+
+func thenAssertion(want ...cqrs.DomainEvent) assertion {
+	return func(t TestingT) Checker {
 		t.Helper()
 
 		return func(got []cqrs.DomainEvent, err error) {
 			t.Helper()
 
-			assert.NoError(t, err)
-			assert.Equal(t, want, got)
+			if assert.NoError(t, err) {
+				assertEvents(t, want, got)
+			}
 		}
 	}
 }
 
 // ThenFailWith asserts that the expected error occurred.
 func ThenFailWith(want error) ThenFn {
-	return func(t *testing.T) Checker {
+	return thenFailWithAssertion(want).then()
+}
+
+func thenFailWithAssertion(want error) assertion {
+	return func(t TestingT) Checker {
 		t.Helper()
 
 		return func(got []cqrs.DomainEvent, err error) {
@@ -95,3 +164,17 @@ func applyEvents(given GivenFn) (cqrs.ESAggregate, error) {
 
 	return agg, nil
 }
+
+// commandRecorder records the commands handled by the aggregate.
+type commandRecorder struct {
+	cqrs.ESAggregate
+
+	commands []cqrs.Command
+}
+
+// Handle implements cqrs.CommandHandler interface.
+func (r *commandRecorder) Handle(c cqrs.Command) ([]cqrs.DomainEvent, error) {
+	r.commands = append(r.commands, c)
+
+	return r.ESAggregate.Handle(c)
+}
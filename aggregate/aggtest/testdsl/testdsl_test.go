@@ -0,0 +1,60 @@
+package testdsl_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/cqrs"
+	"github.com/screwyprof/cqrs/aggregate"
+	"github.com/screwyprof/cqrs/aggregate/aggtest"
+	"github.com/screwyprof/cqrs/aggregate/aggtest/specdoc"
+	. "github.com/screwyprof/cqrs/aggregate/aggtest/testdsl"
+)
+
+func TestWithReporter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it records the scenario which produces events", func(t *testing.T) {
+		t.Parallel()
+
+		ID := aggtest.StringIdentifier("ID")
+		reporter := specdoc.NewReporter()
+
+		Test(t, WithReporter(reporter))(
+			Given(aggregate.FromAggregate(aggtest.NewTestAggregate(ID))),
+			When(aggtest.MakeSomethingHappen{AggID: ID}),
+			Then(aggtest.SomethingHappened{}),
+		)
+
+		want := []specdoc.Scenario{{
+			Aggregate: aggtest.TestAggregateType,
+			Name:      "it records the scenario which produces events",
+			When:      []cqrs.Command{aggtest.MakeSomethingHappen{AggID: ID}},
+			Then:      []cqrs.DomainEvent{aggtest.SomethingHappened{}},
+		}}
+		assert.Equal(t, []specdoc.Feature{{Aggregate: aggtest.TestAggregateType, Scenarios: want}}, reporter.Features())
+	})
+
+	t.Run("it records the scenario which fails", func(t *testing.T) {
+		t.Parallel()
+
+		ID := aggtest.StringIdentifier("ID")
+		reporter := specdoc.NewReporter()
+
+		Test(t, WithReporter(reporter))(
+			Given(aggregate.FromAggregate(aggtest.NewTestAggregate(ID)), aggtest.SomethingHappened{}),
+			When(aggtest.MakeSomethingHappen{AggID: ID}),
+			ThenFailWith(aggtest.ErrItCanHappenOnceOnly),
+		)
+
+		want := []specdoc.Scenario{{
+			Aggregate: aggtest.TestAggregateType,
+			Name:      "it records the scenario which fails",
+			Given:     []cqrs.DomainEvent{aggtest.SomethingHappened{}},
+			When:      []cqrs.Command{aggtest.MakeSomethingHappen{AggID: ID}},
+			Err:       aggtest.ErrItCanHappenOnceOnly,
+		}}
+		assert.Equal(t, []specdoc.Feature{{Aggregate: aggtest.TestAggregateType, Scenarios: want}}, reporter.Features())
+	})
+}
@@ -7,23 +7,32 @@ import (
 	"github.com/screwyprof/cqrs"
 )
 
+// commandHandlerMethods caches the command handler methods found in aggregate types.
+var commandHandlerMethods typeCache[[]keyedMethod] //nolint:gochecknoglobals
+
 // CommandHandler registers and handles commands.
 type CommandHandler struct {
 	handlers map[string]cqrs.CommandHandlerFunc
+	naming   Naming
 }
 
 // NewCommandHandler creates a new instance of CommandHandler.
-func NewCommandHandler() *CommandHandler {
+//
+// Commands are routed according to DefaultNaming unless WithNaming option is given.
+func NewCommandHandler(opts ...Option) *CommandHandler {
 	return &CommandHandler{
 		handlers: make(map[string]cqrs.CommandHandlerFunc),
+		naming:   newOptions(opts...).naming,
 	}
 }
 
 // Handle implements cqrs.CommandHandler interface.
 func (h *CommandHandler) Handle(c cqrs.Command) ([]cqrs.DomainEvent, error) {
-	handler, ok := h.handlers[c.CommandType()]
+	handlerID := h.naming.CommandKey(c)
+
+	handler, ok := h.handlers[handlerID]
 	if !ok {
-		return nil, fmt.Errorf("%w: %s", ErrCommandHandlerNotFound, c.CommandType())
+		return nil, fmt.Errorf("%w: %s", ErrCommandHandlerNotFound, handlerID)
 	}
 
 	return handler(c)
@@ -35,19 +44,57 @@ func (h *CommandHandler) RegisterHandler(method string, handler cqrs.CommandHand
 }
 
 // RegisterHandlers registers all the command handlers found in the aggregate.
+//
+// The handler methods are discovered once per aggregate type and cached,
+// only the receiver is bound for each aggregate instance.
 func (h *CommandHandler) RegisterHandlers(aggregate cqrs.Aggregate) {
-	aggregateType := reflect.TypeOf(aggregate)
+	receiver := reflect.ValueOf(aggregate)
+
+	methods := commandHandlerMethods.load(receiver.Type(), h.naming, func() []keyedMethod {
+		return h.findCommandHandlers(receiver.Type())
+	})
+
+	for _, m := range methods {
+		h.RegisterHandler(m.key, func(c cqrs.Command) ([]cqrs.DomainEvent, error) {
+			if err := m.accepts(c); err != nil {
+				return nil, err
+			}
+
+			return h.invokeCommandHandler(m.method, receiver, c)
+		})
+	}
+}
+
+// RegisterHandlersStrict registers all the command handlers found in the aggregate
+// after verifying that they follow the conventions.
+//
+// It returns a *ConventionError and registers nothing if any of the command handlers is malformed.
+func (h *CommandHandler) RegisterHandlersStrict(aggregate cqrs.Aggregate) error {
+	violations := verifyCommandHandlers(reflect.TypeOf(aggregate), h.naming)
+	if err := newConventionError(aggregate, violations); err != nil {
+		return err
+	}
+
+	h.RegisterHandlers(aggregate)
+
+	return nil
+}
+
+func (h *CommandHandler) findCommandHandlers(aggregateType reflect.Type) []keyedMethod {
+	var methods []keyedMethod
+
 	for i := 0; i < aggregateType.NumMethod(); i++ {
 		method := aggregateType.Method(i)
 
-		if !h.isCommandHandler(method) {
+		key, ok := h.naming.CommandHandlerKey(method)
+		if !ok || !h.isCommandHandler(method) {
 			continue
 		}
 
-		h.RegisterHandler(method.Name, func(c cqrs.Command) ([]cqrs.DomainEvent, error) {
-			return h.invokeCommandHandler(method, aggregate, c)
-		})
+		methods = append(methods, keyedMethod{key: key, method: method})
 	}
+
+	return methods
 }
 
 func (h *CommandHandler) isCommandHandler(method reflect.Method) bool {
@@ -96,39 +143,23 @@ func (h *CommandHandler) eventTypeMethodReturnsString(method reflect.Method) boo
 }
 
 func (h *CommandHandler) invokeCommandHandler(
-	method reflect.Method, aggregate cqrs.Aggregate, c cqrs.Command,
+	method reflect.Method, receiver reflect.Value, c cqrs.Command,
 ) ([]cqrs.DomainEvent, error) {
-	result := method.Func.Call([]reflect.Value{reflect.ValueOf(aggregate), reflect.ValueOf(c)})
+	result := method.Func.Call([]reflect.Value{receiver, reflect.ValueOf(c)})
 
 	resErr := result[1].Interface()
 	if resErr != nil {
 		return nil, resErr.(error) //nolint:forcetypeassert
 	}
 
-	eventsIntf := result[0].Interface()
-	events := h.convertDomainEvents(eventsIntf)
-
-	return events, nil
+	return h.convertDomainEvents(result[0]), nil
 }
 
-func (h *CommandHandler) convertDomainEvents(eventsIntf interface{}) []cqrs.DomainEvent {
-	eventsIntfs := h.interfaceSlice(eventsIntf)
-
-	events := make([]cqrs.DomainEvent, 0, len(eventsIntfs))
-	for _, eventIntf := range eventsIntfs {
-		events = append(events, eventIntf.(cqrs.DomainEvent)) //nolint:forcetypeassert
+func (h *CommandHandler) convertDomainEvents(eventSlice reflect.Value) []cqrs.DomainEvent {
+	events := make([]cqrs.DomainEvent, 0, eventSlice.Len())
+	for i := 0; i < eventSlice.Len(); i++ {
+		events = append(events, eventSlice.Index(i).Interface().(cqrs.DomainEvent)) //nolint:forcetypeassert
 	}
 
 	return events
 }
-
-func (h *CommandHandler) interfaceSlice(slice interface{}) []interface{} {
-	s := reflect.ValueOf(slice)
-
-	ret := make([]interface{}, s.Len())
-	for i := 0; i < s.Len(); i++ {
-		ret[i] = s.Index(i).Interface()
-	}
-
-	return ret
-}
@@ -0,0 +1,316 @@
+package aggregate
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/screwyprof/cqrs"
+	"github.com/screwyprof/cqrs/naming"
+)
+
+var (
+	// commandHandlerViolations caches the command handler violations found in aggregate types.
+	commandHandlerViolations typeCache[[]Violation] //nolint:gochecknoglobals
+
+	// eventApplierViolations caches the event applier violations found in aggregate types.
+	eventApplierViolations typeCache[[]Violation] //nolint:gochecknoglobals
+
+	commandIntfType = reflect.TypeOf((*cqrs.Command)(nil)).Elem()     //nolint:gochecknoglobals
+	eventIntfType   = reflect.TypeOf((*cqrs.DomainEvent)(nil)).Elem() //nolint:gochecknoglobals
+	errorIntfType   = reflect.TypeOf((*error)(nil)).Elem()            //nolint:gochecknoglobals
+)
+
+// Violation describes an aggregate method which does not follow the aggregate conventions.
+type Violation struct {
+	Method string
+	Reason string
+}
+
+// String implements fmt.Stringer interface.
+func (v Violation) String() string {
+	return v.Method + ": " + v.Reason
+}
+
+// ConventionError is returned by strict registration when an aggregate violates the conventions.
+//
+// It holds a detailed report of all the violations found and matches ErrConventionViolation.
+type ConventionError struct {
+	AggregateType string
+	Violations    []Violation
+}
+
+// Error implements error interface.
+func (e *ConventionError) Error() string {
+	var b strings.Builder
+
+	_, _ = fmt.Fprintf(&b, "%s: %s", ErrConventionViolation, e.AggregateType)
+	for _, v := range e.Violations {
+		_, _ = fmt.Fprintf(&b, "\n\t%s", v)
+	}
+
+	return b.String()
+}
+
+// Unwrap makes ConventionError match ErrConventionViolation.
+func (e *ConventionError) Unwrap() error {
+	return ErrConventionViolation
+}
+
+// Verify checks that the aggregate follows the command handler and event applier conventions.
+//
+// The naming convention is DefaultNaming unless WithNaming option is given.
+// It returns a *ConventionError listing every violation found, or nil if there are none.
+func Verify(aggregate cqrs.Aggregate, opts ...Option) error {
+	aggregateType := reflect.TypeOf(aggregate)
+	naming := newOptions(opts...).naming
+
+	var violations []Violation
+	violations = append(violations, verifyCommandHandlers(aggregateType, naming)...)
+	violations = append(violations, verifyEventAppliers(aggregateType, naming)...)
+
+	return newConventionError(aggregate, violations)
+}
+
+func newConventionError(aggregate cqrs.Aggregate, violations []Violation) error {
+	if len(violations) == 0 {
+		return nil
+	}
+
+	return &ConventionError{
+		AggregateType: aggregate.AggregateType(),
+		Violations:    violations,
+	}
+}
+
+func verifyCommandHandlers(aggregateType reflect.Type, naming Naming) []Violation {
+	return commandHandlerViolations.load(aggregateType, naming, func() []Violation {
+		return findCommandHandlerViolations(aggregateType, naming)
+	})
+}
+
+func findCommandHandlerViolations(aggregateType reflect.Type, naming Naming) []Violation {
+	var violations []Violation
+
+	collisions := newCollisions()
+
+	for i := 0; i < aggregateType.NumMethod(); i++ {
+		method := aggregateType.Method(i)
+
+		if !looksLikeCommandHandler(method) {
+			continue
+		}
+
+		reason := commandHandlerSignatureViolation(method)
+		if reason != "" {
+			violations = append(violations, Violation{Method: method.Name, Reason: reason})
+		}
+
+		message, ok := messageOf(method, commandIntfType)
+		if !ok {
+			continue
+		}
+
+		command := message.(cqrs.Command) //nolint:forcetypeassert
+
+		commandKey := naming.CommandKey(command)
+		collisions.add(commandKey, method)
+
+		// the signature violation is the reason the handler is not invoked, the routing is not worth reporting.
+		if reason != "" {
+			continue
+		}
+
+		if reason := commandHandlerRoutingViolation(method, command, commandKey, naming); reason != "" {
+			violations = append(violations, Violation{Method: method.Name, Reason: reason})
+		}
+	}
+
+	return append(violations, collisions.violations()...)
+}
+
+// commandHandlerRoutingViolation checks that the command the method accepts is routed to the method.
+func commandHandlerRoutingViolation(
+	method reflect.Method, command cqrs.Command, commandKey string, naming Naming,
+) string {
+	key, ok := naming.CommandHandlerKey(method)
+	if !ok {
+		return fmt.Sprintf(
+			"command type %q is routed to %q but the method is not registered as a command handler, "+
+				"the handler is never invoked",
+			command.CommandType(), commandKey,
+		)
+	}
+
+	if key != commandKey {
+		return fmt.Sprintf(
+			"command type %q is routed to %q but the method is registered under %q, the handler is never invoked",
+			command.CommandType(), commandKey, key,
+		)
+	}
+
+	return ""
+}
+
+// collisions tracks the methods which handle the same routing key.
+type collisions struct {
+	keys    []string
+	methods map[string][]reflect.Method
+}
+
+func newCollisions() *collisions {
+	return &collisions{methods: make(map[string][]reflect.Method)}
+}
+
+func (c *collisions) add(key string, method reflect.Method) {
+	if _, seen := c.methods[key]; !seen {
+		c.keys = append(c.keys, key)
+	}
+
+	c.methods[key] = append(c.methods[key], method)
+}
+
+func (c *collisions) violations() []Violation {
+	var violations []Violation
+
+	for _, key := range c.keys {
+		methods := c.methods[key]
+		if len(methods) < 2 {
+			continue
+		}
+
+		names := make([]string, 0, len(methods))
+		params := make([]string, 0, len(methods))
+
+		for _, method := range methods {
+			names = append(names, method.Name)
+			params = append(params, naming.TypeKey(method.Type.In(1)))
+		}
+
+		violations = append(violations, Violation{
+			Method: strings.Join(names, ", "),
+			Reason: fmt.Sprintf("routing key %q collides across %s", key, strings.Join(params, ", ")),
+		})
+	}
+
+	return violations
+}
+
+// looksLikeCommandHandler tells whether the method is meant to be a command handler:
+// it either accepts a command or returns a list of events with an error.
+func looksLikeCommandHandler(method reflect.Method) bool {
+	for i := 1; i < method.Type.NumIn(); i++ {
+		if method.Type.In(i).Implements(commandIntfType) {
+			return true
+		}
+	}
+
+	return method.Type.NumOut() == 2 &&
+		method.Type.Out(0).Kind() == reflect.Slice &&
+		method.Type.Out(1) == errorIntfType
+}
+
+func commandHandlerSignatureViolation(method reflect.Method) string {
+	handler := &CommandHandler{}
+
+	if !handler.commandHandlerHasExpectedInputs(method) {
+		return "a command handler must accept exactly one command"
+	}
+
+	if !handler.commandHandlerHasExpectedOutputs(method) {
+		return "a command handler must return a list of events and an error"
+	}
+
+	return ""
+}
+
+func verifyEventAppliers(aggregateType reflect.Type, naming Naming) []Violation {
+	return eventApplierViolations.load(aggregateType, naming, func() []Violation {
+		return findEventApplierViolations(aggregateType, naming)
+	})
+}
+
+func findEventApplierViolations(aggregateType reflect.Type, naming Naming) []Violation {
+	var violations []Violation
+
+	collisions := newCollisions()
+
+	for i := 0; i < aggregateType.NumMethod(); i++ {
+		method := aggregateType.Method(i)
+
+		key, ok := naming.EventApplierKey(method)
+		if !ok {
+			continue
+		}
+
+		if reason := eventApplierSignatureViolation(method); reason != "" {
+			violations = append(violations, Violation{Method: method.Name, Reason: reason})
+
+			continue
+		}
+
+		message, ok := messageOf(method, eventIntfType)
+		if !ok {
+			continue
+		}
+
+		event := message.(cqrs.DomainEvent) //nolint:forcetypeassert
+
+		eventKey := naming.EventKey(event)
+		collisions.add(eventKey, method)
+
+		if key != eventKey {
+			violations = append(violations, Violation{
+				Method: method.Name,
+				Reason: fmt.Sprintf(
+					"event type %q is routed to %q but the method is registered under %q, the applier is never invoked",
+					event.EventType(), eventKey, key,
+				),
+			})
+		}
+	}
+
+	return append(violations, collisions.violations()...)
+}
+
+func eventApplierSignatureViolation(method reflect.Method) string {
+	if method.Type.NumIn() != 2 {
+		return "an event applier must accept exactly one event"
+	}
+
+	if !method.Type.In(1).Implements(eventIntfType) {
+		return fmt.Sprintf("an event applier must accept an event, got %s", method.Type.In(1))
+	}
+
+	if method.Type.NumOut() > 1 || method.Type.NumOut() == 1 && method.Type.Out(0) != errorIntfType {
+		return "an event applier must return either nothing or an error"
+	}
+
+	return ""
+}
+
+// messageOf returns a zero value of the concrete command or event the method accepts.
+func messageOf(method reflect.Method, intfType reflect.Type) (any, bool) {
+	if method.Type.NumIn() != 2 {
+		return nil, false
+	}
+
+	message, ok := zeroValueOf(method.Type.In(1))
+	if !ok || !message.Type().Implements(intfType) {
+		return nil, false
+	}
+
+	return message.Interface(), true
+}
+
+// zeroValueOf returns a usable zero value of the given concrete type.
+func zeroValueOf(t reflect.Type) (reflect.Value, bool) {
+	switch t.Kind() { //nolint:exhaustive
+	case reflect.Interface:
+		return reflect.Value{}, false
+	case reflect.Pointer:
+		return reflect.New(t.Elem()), true
+	default:
+		return reflect.Zero(t), true
+	}
+}
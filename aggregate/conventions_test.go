@@ -0,0 +1,172 @@
+package aggregate_test
+
+import (
+	"testing"
+
+	"github.com/go-faker/faker/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/cqrs/aggregate"
+	"github.com/screwyprof/cqrs/aggregate/aggtest"
+)
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it accepts an aggregate which follows the conventions", func(t *testing.T) {
+		t.Parallel()
+
+		err := aggregate.Verify(aggtest.NewTestAggregate(aggtest.StringIdentifier(faker.UUIDHyphenated())))
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("it reports every violation found", func(t *testing.T) {
+		t.Parallel()
+
+		// act
+		err := aggregate.Verify(aggtest.NewMalformedAggregate(aggtest.StringIdentifier(faker.UUIDHyphenated())))
+
+		// assert
+		assert.ErrorIs(t, err, aggregate.ErrConventionViolation)
+
+		var conventionErr *aggregate.ConventionError
+		assert.ErrorAs(t, err, &conventionErr)
+		assert.Equal(t, aggtest.MalformedAggregateType, conventionErr.AggregateType)
+		assert.ElementsMatch(t, []string{
+			"MakeSomethingHappen",
+			"MakeSomethingHappenAgain",
+			"MakeSomethingHappen, MakeSomethingHappenAgain",
+			"OnSomethingHappened",
+			"OnSomethingStrangeHappened",
+		}, violatingMethods(conventionErr))
+	})
+}
+
+func TestVerifyReasons(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it reports the signature of a handler rather than its routing", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		naming := &aggregate.MethodNaming{CommandHandlerPrefix: "Handle", EventApplierPrefix: "On"}
+
+		// act
+		err := aggregate.Verify(
+			aggtest.NewMalformedAggregate(aggtest.StringIdentifier(faker.UUIDHyphenated())),
+			aggregate.WithNaming(naming),
+		)
+
+		// assert
+		var conventionErr *aggregate.ConventionError
+		assert.ErrorAs(t, err, &conventionErr)
+		assert.Equal(t, []string{"a command handler must return a list of events and an error"},
+			violationReasons(conventionErr, "MakeSomethingHappen"))
+	})
+
+	t.Run("it reports the key a handler is registered under and the key the command is routed to", func(t *testing.T) {
+		t.Parallel()
+
+		// act
+		err := aggregate.Verify(aggtest.NewMalformedAggregate(aggtest.StringIdentifier(faker.UUIDHyphenated())))
+
+		// assert
+		var conventionErr *aggregate.ConventionError
+		assert.ErrorAs(t, err, &conventionErr)
+		assert.Equal(t, []string{
+			`command type "MakeSomethingHappen" is routed to "MakeSomethingHappen" ` +
+				`but the method is registered under "MakeSomethingHappenAgain", the handler is never invoked`,
+		}, violationReasons(conventionErr, "MakeSomethingHappenAgain"))
+	})
+}
+
+func TestStrictRegistration(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it registers the command handlers of a valid aggregate", func(t *testing.T) {
+		t.Parallel()
+
+		handler := aggregate.NewCommandHandler()
+
+		err := handler.RegisterHandlersStrict(aggtest.NewTestAggregate(aggtest.StringIdentifier(faker.UUIDHyphenated())))
+		assert.NoError(t, err)
+
+		_, err = handler.Handle(aggtest.MakeSomethingHappen{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("it does not register the command handlers of a malformed aggregate", func(t *testing.T) {
+		t.Parallel()
+
+		handler := aggregate.NewCommandHandler()
+
+		err := handler.RegisterHandlersStrict(aggtest.NewMalformedAggregate(aggtest.StringIdentifier(faker.UUIDHyphenated())))
+		assert.ErrorIs(t, err, aggregate.ErrConventionViolation)
+
+		_, err = handler.Handle(aggtest.MakeSomethingHappen{})
+		assert.ErrorIs(t, err, aggregate.ErrCommandHandlerNotFound)
+	})
+
+	t.Run("it registers the event appliers of a valid aggregate", func(t *testing.T) {
+		t.Parallel()
+
+		applier := aggregate.NewEventApplier()
+
+		err := applier.RegisterAppliersStrict(aggtest.NewTestAggregate(aggtest.StringIdentifier(faker.UUIDHyphenated())))
+		assert.NoError(t, err)
+
+		err = applier.Apply(aggtest.SomethingHappened{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("it does not register the event appliers of a malformed aggregate", func(t *testing.T) {
+		t.Parallel()
+
+		applier := aggregate.NewEventApplier()
+
+		err := applier.RegisterAppliersStrict(aggtest.NewMalformedAggregate(aggtest.StringIdentifier(faker.UUIDHyphenated())))
+		assert.ErrorIs(t, err, aggregate.ErrConventionViolation)
+
+		err = applier.Apply(aggtest.SomethingElseHappened{})
+		assert.ErrorIs(t, err, aggregate.ErrEventApplierNotFound)
+	})
+
+	t.Run("it converts a valid aggregate", func(t *testing.T) {
+		t.Parallel()
+
+		agg, err := aggregate.FromAggregateStrict(aggtest.NewTestAggregate(aggtest.StringIdentifier(faker.UUIDHyphenated())))
+
+		assert.NoError(t, err)
+		assert.NotNil(t, agg)
+	})
+
+	t.Run("it fails to convert a malformed aggregate", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := aggregate.FromAggregateStrict(aggtest.NewMalformedAggregate(aggtest.StringIdentifier(faker.UUIDHyphenated())))
+
+		assert.ErrorIs(t, err, aggregate.ErrConventionViolation)
+	})
+}
+
+func violatingMethods(err *aggregate.ConventionError) []string {
+	methods := make([]string, 0, len(err.Violations))
+	for _, v := range err.Violations {
+		methods = append(methods, v.Method)
+	}
+
+	return methods
+}
+
+func violationReasons(err *aggregate.ConventionError, method string) []string {
+	var reasons []string
+
+	for _, v := range err.Violations {
+		if v.Method == method {
+			reasons = append(reasons, v.Reason)
+		}
+	}
+
+	return reasons
+}
@@ -0,0 +1,123 @@
+package aggregate
+
+import (
+	"fmt"
+
+	"github.com/screwyprof/cqrs"
+)
+
+// Decider is a functional aggregate model which is an alternative to the reflection based aggregates.
+//
+// Decide makes a decision on the given command based on the current state and returns the resulting events.
+// Evolve returns the state obtained by applying the given event to the current state.
+// Initial returns the state of a new aggregate.
+//
+// The functions are expected to be pure, they must not mutate the given state.
+// C and E are usually interfaces which all the commands and events of the aggregate implement.
+type Decider[S any, C cqrs.Command, E cqrs.DomainEvent] struct {
+	Decide  func(state S, command C) ([]E, error)
+	Evolve  func(state S, event E) S
+	Initial func() S
+}
+
+// FromDecider turns a Decider into a cqrs.ESAggregate with the given identifier and type.
+//
+// The result works with aggregate.Factory, the aggregate stores and dispatchers the same way
+// the aggregates converted with FromAggregate do.
+func FromDecider[S any, C cqrs.Command, E cqrs.DomainEvent](
+	id cqrs.Identifier, aggregateType string, decider Decider[S, C, E],
+) *EventSourced {
+	if decider.Decide == nil {
+		panic("decide is required")
+	}
+
+	if decider.Evolve == nil {
+		panic("evolve is required")
+	}
+
+	if decider.Initial == nil {
+		panic("initial is required")
+	}
+
+	newAggregate := func() *DeciderAggregate[S, C, E] {
+		return &DeciderAggregate[S, C, E]{
+			id:            id,
+			aggregateType: aggregateType,
+			decider:       decider,
+			state:         decider.Initial(),
+		}
+	}
+
+	agg := newAggregate()
+
+	es := New(agg, agg, agg)
+	es.fresh = func() cqrs.Aggregate {
+		return newAggregate()
+	}
+
+	return es
+}
+
+// DeciderAggregate holds the current state of an aggregate driven by a Decider.
+//
+// It implements cqrs.Aggregate, cqrs.CommandHandler and cqrs.EventApplier interfaces.
+type DeciderAggregate[S any, C cqrs.Command, E cqrs.DomainEvent] struct {
+	id            cqrs.Identifier
+	aggregateType string
+
+	decider Decider[S, C, E]
+	state   S
+}
+
+// AggregateID implements cqrs.Aggregate interface.
+func (a *DeciderAggregate[S, C, E]) AggregateID() cqrs.Identifier {
+	return a.id
+}
+
+// AggregateType implements cqrs.Aggregate interface.
+func (a *DeciderAggregate[S, C, E]) AggregateType() string {
+	return a.aggregateType
+}
+
+// State returns the current state of the aggregate.
+func (a *DeciderAggregate[S, C, E]) State() S {
+	return a.state
+}
+
+// Handle implements cqrs.CommandHandler interface.
+//
+// It returns ErrCommandHandlerNotFound if the command is not of the type the decider accepts.
+func (a *DeciderAggregate[S, C, E]) Handle(c cqrs.Command) ([]cqrs.DomainEvent, error) {
+	command, ok := c.(C)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrCommandHandlerNotFound, c.CommandType())
+	}
+
+	decided, err := a.decider.Decide(a.state, command)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]cqrs.DomainEvent, 0, len(decided))
+	for _, e := range decided {
+		events = append(events, e)
+	}
+
+	return events, nil
+}
+
+// Apply implements cqrs.EventApplier interface.
+//
+// It returns ErrEventApplierNotFound if an event is not of the type the decider accepts.
+func (a *DeciderAggregate[S, C, E]) Apply(events ...cqrs.DomainEvent) error {
+	for _, e := range events {
+		event, ok := e.(E)
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrEventApplierNotFound, e.EventType())
+		}
+
+		a.state = a.decider.Evolve(a.state, event)
+	}
+
+	return nil
+}
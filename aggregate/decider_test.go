@@ -0,0 +1,128 @@
+package aggregate_test
+
+import (
+	"testing"
+
+	"github.com/go-faker/faker/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/cqrs"
+	"github.com/screwyprof/cqrs/aggregate"
+	"github.com/screwyprof/cqrs/aggregate/aggtest"
+	. "github.com/screwyprof/cqrs/aggregate/aggtest/testdsl"
+)
+
+func TestFromDecider(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it panics if the decider is incomplete", func(t *testing.T) {
+		t.Parallel()
+
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+
+		noDecide := aggtest.NewTestDecider()
+		noDecide.Decide = nil
+
+		noEvolve := aggtest.NewTestDecider()
+		noEvolve.Evolve = nil
+
+		noInitial := aggtest.NewTestDecider()
+		noInitial.Initial = nil
+
+		assert.Panics(t, func() { aggregate.FromDecider(ID, aggtest.TestAggregateType, noDecide) })
+		assert.Panics(t, func() { aggregate.FromDecider(ID, aggtest.TestAggregateType, noEvolve) })
+		assert.Panics(t, func() { aggregate.FromDecider(ID, aggtest.TestAggregateType, noInitial) })
+	})
+
+	t.Run("it decides on a command", func(t *testing.T) {
+		t.Parallel()
+
+		Test(t)(
+			Given(createTestDeciderAggregate()),
+			When(aggtest.MakeSomethingHappen{}),
+			Then(aggtest.SomethingHappened{}),
+		)
+	})
+
+	t.Run("it decides on the evolved state", func(t *testing.T) {
+		t.Parallel()
+
+		Test(t)(
+			Given(createTestDeciderAggregate(), aggtest.SomethingHappened{}),
+			When(aggtest.MakeSomethingHappen{}),
+			ThenFailWith(aggtest.ErrItCanHappenOnceOnly),
+		)
+	})
+
+	t.Run("it fails on a command the decider does not accept", func(t *testing.T) {
+		t.Parallel()
+
+		Test(t)(
+			Given(createTestDeciderAggregate()),
+			When(aggtest.MakeSomethingHappenElsewhere{}),
+			ThenFailWith(aggregate.ErrCommandHandlerNotFound),
+		)
+	})
+
+	t.Run("it fails on an event the decider does not accept", func(t *testing.T) {
+		t.Parallel()
+
+		agg := aggregate.FromDecider(
+			aggtest.StringIdentifier(faker.UUIDHyphenated()),
+			aggtest.TestAggregateType,
+			aggregate.Decider[int, aggtest.MakeSomethingHappen, aggtest.SomethingHappened]{
+				Decide:  func(int, aggtest.MakeSomethingHappen) ([]aggtest.SomethingHappened, error) { return nil, nil },
+				Evolve:  func(state int, _ aggtest.SomethingHappened) int { return state + 1 },
+				Initial: func() int { return 0 },
+			},
+		)
+
+		err := agg.Apply(aggtest.SomethingElseHappened{})
+
+		assert.ErrorIs(t, err, aggregate.ErrEventApplierNotFound)
+	})
+
+	t.Run("it evolves the state and increments the version", func(t *testing.T) {
+		t.Parallel()
+
+		agg := createTestDeciderAggregate()
+
+		err := agg.Apply(aggtest.SomethingHappened{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, agg.Version())
+		assert.True(t, deciderStateOf(agg).AlreadyHappened)
+	})
+
+	t.Run("it is created by the factory", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+
+		f := aggregate.NewFactory()
+		f.RegisterAggregate(aggtest.TestAggregateType, func(ID cqrs.Identifier) cqrs.ESAggregate {
+			return aggregate.FromDecider(ID, aggtest.TestAggregateType, aggtest.NewTestDecider())
+		})
+
+		// act
+		agg, err := f.CreateAggregate(aggtest.TestAggregateType, ID)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, ID, agg.AggregateID())
+		assert.Equal(t, aggtest.TestAggregateType, agg.AggregateType())
+	})
+}
+
+type testDeciderAggregate = aggregate.DeciderAggregate[aggtest.TestState, aggtest.MakeSomethingHappen, aggtest.Event]
+
+func createTestDeciderAggregate() *aggregate.EventSourced {
+	ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+
+	return aggregate.FromDecider(ID, aggtest.TestAggregateType, aggtest.NewTestDecider())
+}
+
+func deciderStateOf(agg *aggregate.EventSourced) aggtest.TestState {
+	return agg.Aggregate.(*testDeciderAggregate).State() //nolint:forcetypeassert
+}
@@ -3,36 +3,93 @@ package aggregate
 import (
 	"fmt"
 	"reflect"
-	"strings"
 
 	"github.com/screwyprof/cqrs"
 )
 
+// eventApplierMethods caches the event applier methods found in aggregate types.
+var eventApplierMethods typeCache[[]keyedMethod] //nolint:gochecknoglobals
+
 // EventApplier applies events for the registered appliers.
 type EventApplier struct {
 	appliers map[string]cqrs.EventApplierFunc
+	naming   Naming
 }
 
 // NewEventApplier creates a new instance of EventApplier.
-func NewEventApplier() *EventApplier {
+//
+// Events are routed according to DefaultNaming unless WithNaming option is given.
+func NewEventApplier(opts ...Option) *EventApplier {
 	return &EventApplier{
 		appliers: make(map[string]cqrs.EventApplierFunc),
+		naming:   newOptions(opts...).naming,
 	}
 }
 
 // RegisterAppliers registers all the event appliers found in the aggregate.
+//
+// An applier either returns nothing or an error, e.g. when it discovers a corrupt history.
+// The applier methods are discovered once per aggregate type and cached,
+// only the receiver is bound for each aggregate instance.
 func (a *EventApplier) RegisterAppliers(aggregate cqrs.Aggregate) {
-	aggregateType := reflect.TypeOf(aggregate)
+	receiver := reflect.ValueOf(aggregate)
+
+	methods := eventApplierMethods.load(receiver.Type(), a.naming, func() []keyedMethod {
+		return a.findAppliers(receiver.Type())
+	})
+
+	for _, m := range methods {
+		a.RegisterApplier(m.key, func(e cqrs.DomainEvent) error {
+			if err := m.accepts(e); err != nil {
+				return err
+			}
+
+			return a.invokeEventApplier(m.method, receiver, e)
+		})
+	}
+}
+
+func (a *EventApplier) invokeEventApplier(method reflect.Method, receiver reflect.Value, e cqrs.DomainEvent) error {
+	result := method.Func.Call([]reflect.Value{receiver, reflect.ValueOf(e)})
+
+	// the values returned by an applier other than a single error are ignored.
+	if len(result) != 1 || method.Type.Out(0) != errorIntfType || result[0].IsNil() {
+		return nil
+	}
+
+	return result[0].Interface().(error) //nolint:forcetypeassert
+}
+
+// RegisterAppliersStrict registers all the event appliers found in the aggregate
+// after verifying that they follow the conventions.
+//
+// It returns a *ConventionError and registers nothing if any of the event appliers is malformed.
+func (a *EventApplier) RegisterAppliersStrict(aggregate cqrs.Aggregate) error {
+	violations := verifyEventAppliers(reflect.TypeOf(aggregate), a.naming)
+	if err := newConventionError(aggregate, violations); err != nil {
+		return err
+	}
+
+	a.RegisterAppliers(aggregate)
+
+	return nil
+}
+
+func (a *EventApplier) findAppliers(aggregateType reflect.Type) []keyedMethod {
+	var methods []keyedMethod
+
 	for i := 0; i < aggregateType.NumMethod(); i++ {
 		method := aggregateType.Method(i)
-		if !strings.HasPrefix(method.Name, "On") {
+
+		key, ok := a.naming.EventApplierKey(method)
+		if !ok {
 			continue
 		}
 
-		a.RegisterApplier(method.Name, func(e cqrs.DomainEvent) {
-			method.Func.Call([]reflect.Value{reflect.ValueOf(aggregate), reflect.ValueOf(e)})
-		})
+		methods = append(methods, keyedMethod{key: key, method: method})
 	}
+
+	return methods
 }
 
 // RegisterApplier registers an event applier for the given method.
@@ -52,14 +109,12 @@ func (a *EventApplier) Apply(events ...cqrs.DomainEvent) error {
 }
 
 func (a *EventApplier) apply(event cqrs.DomainEvent) error {
-	applierID := "On" + event.EventType()
+	applierID := a.naming.EventKey(event)
 
 	applier, ok := a.appliers[applierID]
 	if !ok {
 		return fmt.Errorf("%w: %s", ErrEventApplierNotFound, applierID)
 	}
 
-	applier(event)
-
-	return nil
+	return applier(event)
 }
@@ -1,15 +1,49 @@
 package aggregate
 
 import (
+	"fmt"
+	"reflect"
+	"slices"
+
 	"github.com/screwyprof/cqrs"
 )
 
+// ApplyError is returned when an event cannot be applied to the aggregate.
+//
+// It tells which event failed and wraps the error returned by the event applier.
+type ApplyError struct {
+	Index     int
+	EventType string
+	Err       error
+}
+
+// Error implements error interface.
+func (e *ApplyError) Error() string {
+	return fmt.Sprintf("cannot apply event #%d %s: %v", e.Index, e.EventType, e.Err)
+}
+
+// Unwrap returns the error returned by the event applier.
+func (e *ApplyError) Unwrap() error {
+	return e.Err
+}
+
 // EventSourced is an aggregate that implements CQRS and Event Sourcing.
 //
 // It composes cqrs.Aggregate, cqrs.CommandHandler, and cqrs.EventApplier interfaces.
+//
+// It keeps track of the events produced by the handled commands until they are marked as committed,
+// so that several commands can be handled against a loaded aggregate before it is stored once.
 type EventSourced struct {
 	cqrs.Aggregate
 	version int
+	changes []cqrs.DomainEvent
+
+	// history holds the events the aggregate has been loaded with and the committed changes.
+	history []cqrs.DomainEvent
+
+	// fresh creates a new instance of the aggregate the state is restored from, it is nil if unknown.
+	fresh        func() cqrs.Aggregate
+	inconsistent error
 
 	commandHandler cqrs.CommandHandler
 	eventApplier   cqrs.EventApplier
@@ -39,41 +73,125 @@ func New(aggregate cqrs.Aggregate, commandHandler cqrs.CommandHandler, eventAppl
 	}
 }
 
-// Version returns the current version of the aggregate.
+// Version returns the version of the aggregate the uncommitted changes are based on.
 //
-// It implements the cqrs.Versionable interface.
+// It is the same as OriginalVersion and implements the cqrs.Versionable interface.
 func (b *EventSourced) Version() int {
 	return b.version
 }
 
+// OriginalVersion returns the version of the aggregate as it was loaded or last committed.
+func (b *EventSourced) OriginalVersion() int {
+	return b.version
+}
+
+// CurrentVersion returns the version of the aggregate including the uncommitted changes.
+func (b *EventSourced) CurrentVersion() int {
+	return b.version + len(b.changes)
+}
+
+// Changes returns the events produced since the aggregate was loaded or last committed.
+func (b *EventSourced) Changes() []cqrs.DomainEvent {
+	changes := make([]cqrs.DomainEvent, len(b.changes))
+	copy(changes, b.changes)
+
+	return changes
+}
+
+// MarkCommitted marks the uncommitted changes as persisted and advances the original version.
+func (b *EventSourced) MarkCommitted() {
+	b.history = append(b.history, b.changes...)
+	b.version += len(b.changes)
+	b.changes = nil
+}
+
 // Handle processes the given command and produces relevant domain events.
 //
-// It handles this given command and applies the produced events.
+// It handles this given command, applies the produced events and records them as uncommitted changes.
+//
+// The events are applied all or nothing: if one of them fails, the state is rebuilt from the history
+// and the changes, so the aggregate can handle further commands as if the command has never been handled.
+// The state can be rebuilt only for the aggregates created by Factory or FromDecider, any other aggregate
+// returns ErrInconsistentAggregate from then on.
 //
 // It implements the cqrs.CommandHandler interface.
 func (b *EventSourced) Handle(c cqrs.Command) ([]cqrs.DomainEvent, error) {
+	if b.inconsistent != nil {
+		return nil, b.inconsistent
+	}
+
 	events, err := b.commandHandler.Handle(c)
 	if err != nil {
 		return nil, err
 	}
 
-	if applierErr := b.eventApplier.Apply(events...); applierErr != nil {
+	if applierErr := b.applyEach(events); applierErr != nil {
+		b.restore(applierErr)
+
 		return nil, applierErr
 	}
 
+	b.changes = append(b.changes, events...)
+
 	return events, nil
 }
 
 // Apply applies the given domain events to the aggregate.
 //
-// It applies the events and updates the aggregate version.
+// It applies the already persisted events, e.g. when the aggregate is loaded, and updates the aggregate version.
+// It returns an *ApplyError for the first event which cannot be applied, the state is restored as in Handle.
 // It implements the cqrs.EventApplier interface.
 func (b *EventSourced) Apply(e ...cqrs.DomainEvent) error {
-	if err := b.eventApplier.Apply(e...); err != nil {
+	if b.inconsistent != nil {
+		return b.inconsistent
+	}
+
+	if err := b.applyEach(e); err != nil {
+		b.restore(err)
+
 		return err
 	}
 
+	b.history = append(b.history, e...)
 	b.version += len(e)
 
 	return nil
 }
+
+// restore rebuilds the state from a fresh instance of the aggregate, so that a partially applied batch
+// of events leaves no trace. The fresh state replaces the current one in place, as the command handlers
+// and the event appliers are bound to the aggregate instance.
+//
+// If the state cannot be rebuilt, the aggregate refuses any further command or event with the given cause.
+func (b *EventSourced) restore(cause error) {
+	if !b.rebuild() {
+		b.inconsistent = fmt.Errorf("%w: %s %s: %w",
+			ErrInconsistentAggregate, b.AggregateType(), b.AggregateID(), cause)
+	}
+}
+
+// rebuild replays the history and the changes on a fresh instance, it reports whether the state is rebuilt.
+func (b *EventSourced) rebuild() bool {
+	if b.fresh == nil {
+		return false
+	}
+
+	current, fresh := reflect.ValueOf(b.Aggregate), reflect.ValueOf(b.fresh())
+	if current.Kind() != reflect.Pointer || !fresh.IsValid() || fresh.Type() != current.Type() || fresh.IsNil() {
+		return false
+	}
+
+	current.Elem().Set(fresh.Elem())
+
+	return b.applyEach(slices.Concat(b.history, b.changes)) == nil
+}
+
+func (b *EventSourced) applyEach(events []cqrs.DomainEvent) error {
+	for i, e := range events {
+		if err := b.eventApplier.Apply(e); err != nil {
+			return &ApplyError{Index: i, EventType: e.EventType(), Err: err}
+		}
+	}
+
+	return nil
+}
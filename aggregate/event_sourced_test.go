@@ -112,6 +112,106 @@ func TestEventSourced(t *testing.T) {
 		})
 	})
 
+	t.Run("tracking changes", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("it records the events produced by the handled commands", func(t *testing.T) {
+			t.Parallel()
+
+			agg := createTestAggWithDefaultCommandHandlerAndEventApplier()
+
+			events, err := agg.Handle(domain.MakeSomethingHappen{})
+
+			assert.NoError(t, err)
+			assert.Equal(t, events, agg.Changes())
+			assert.Equal(t, 0, agg.OriginalVersion())
+			assert.Equal(t, 1, agg.CurrentVersion())
+		})
+
+		t.Run("it does not record the applied events", func(t *testing.T) {
+			t.Parallel()
+
+			agg := createTestAggWithDefaultCommandHandlerAndEventApplier()
+
+			err := agg.Apply(domain.SomethingHappened{})
+
+			assert.NoError(t, err)
+			assert.Empty(t, agg.Changes())
+			assert.Equal(t, 1, agg.OriginalVersion())
+			assert.Equal(t, 1, agg.CurrentVersion())
+		})
+
+		t.Run("it does not record the events of a failed command", func(t *testing.T) {
+			t.Parallel()
+
+			agg := createTestAggWithDefaultCommandHandlerAndEventApplier()
+
+			_, err := agg.Handle(domain.MakeSomethingHappen{})
+			assert.NoError(t, err)
+
+			_, err = agg.Handle(domain.MakeSomethingHappen{})
+			assert.ErrorIs(t, err, domain.ErrItCanHappenOnceOnly)
+
+			assert.Equal(t, []cqrs.DomainEvent{domain.SomethingHappened{}}, agg.Changes())
+		})
+
+		t.Run("it restores the state if it cannot apply the second of the produced events", func(t *testing.T) {
+			t.Parallel()
+
+			ID := domain.StringIdentifier(faker.UUIDHyphenated())
+
+			f := aggregate.NewFactory()
+			f.RegisterAggregate("mock.BatchAggregate", func(ID cqrs.Identifier) cqrs.ESAggregate {
+				return f.FromAggregate(&batchAggregate{id: ID})
+			})
+
+			created, err := f.CreateAggregate("mock.BatchAggregate", ID)
+			assert.NoError(t, err)
+
+			agg := created.(*aggregate.EventSourced) //nolint:forcetypeassert
+			assert.NoError(t, agg.Apply(domain.SomethingHappened{}))
+
+			_, err = agg.Handle(domain.MakeSomethingHappen{})
+
+			var applyErr *aggregate.ApplyError
+			assert.ErrorAs(t, err, &applyErr)
+			assert.Equal(t, 1, applyErr.Index)
+			assert.Empty(t, agg.Changes())
+			assert.Equal(t, 1, agg.CurrentVersion())
+			assert.Equal(t, 1, agg.Aggregate.(*batchAggregate).applied) //nolint:forcetypeassert
+		})
+
+		t.Run("it becomes inconsistent if it cannot restore the state", func(t *testing.T) {
+			t.Parallel()
+
+			agg := aggregate.FromAggregate(&batchAggregate{id: domain.StringIdentifier(faker.UUIDHyphenated())})
+
+			_, err := agg.Handle(domain.MakeSomethingHappen{})
+			assert.ErrorIs(t, err, domain.ErrImpossibleTransition)
+
+			_, err = agg.Handle(domain.MakeSomethingHappen{})
+			assert.ErrorIs(t, err, aggregate.ErrInconsistentAggregate)
+			assert.ErrorIs(t, agg.Apply(domain.SomethingHappened{}), aggregate.ErrInconsistentAggregate)
+			assert.Empty(t, agg.Changes())
+		})
+
+		t.Run("it advances the original version when the changes are committed", func(t *testing.T) {
+			t.Parallel()
+
+			agg := createTestAggWithDefaultCommandHandlerAndEventApplier()
+
+			_, err := agg.Handle(domain.MakeSomethingHappen{})
+			assert.NoError(t, err)
+
+			agg.MarkCommitted()
+
+			assert.Empty(t, agg.Changes())
+			assert.Equal(t, 1, agg.OriginalVersion())
+			assert.Equal(t, 1, agg.Version())
+			assert.Equal(t, 1, agg.CurrentVersion())
+		})
+	})
+
 	t.Run("applying events", func(t *testing.T) {
 		t.Parallel()
 
@@ -123,6 +223,32 @@ func TestEventSourced(t *testing.T) {
 			assert.ErrorIs(t, err, aggregate.ErrEventApplierNotFound)
 		})
 
+		t.Run("it returns the failing event if an event applier fails", func(t *testing.T) {
+			t.Parallel()
+
+			agg := createTestAggWithDefaultCommandHandlerAndEventApplier()
+
+			err := agg.Apply(domain.SomethingHappened{}, domain.SomethingImpossibleHappened{})
+
+			var applyErr *aggregate.ApplyError
+			assert.ErrorAs(t, err, &applyErr)
+			assert.ErrorIs(t, err, domain.ErrImpossibleTransition)
+			assert.Equal(t, 1, applyErr.Index)
+			assert.Equal(t, "SomethingImpossibleHappened", applyErr.EventType)
+			assert.Equal(t, 0, agg.Version())
+		})
+
+		t.Run("it ignores the values an event applier returns other than an error", func(t *testing.T) {
+			t.Parallel()
+
+			agg := aggregate.FromAggregate(&countingAggregate{id: domain.StringIdentifier(faker.UUIDHyphenated())})
+
+			err := agg.Apply(domain.SomethingHappened{}, domain.SomethingElseHappened{})
+
+			assert.NoError(t, err)
+			assert.Equal(t, 2, agg.Version())
+		})
+
 		t.Run("it increments the aggregate version", func(t *testing.T) {
 			t.Parallel()
 
@@ -178,8 +304,10 @@ func createTestAggWithEmptyEventApplier() *aggregate.EventSourced {
 
 func createEventApplier(agg *domain.TestAggregate) *aggregate.EventApplier {
 	eventApplier := aggregate.NewEventApplier()
-	eventApplier.RegisterApplier("OnSomethingHappened", func(e cqrs.DomainEvent) {
+	eventApplier.RegisterApplier("OnSomethingHappened", func(e cqrs.DomainEvent) error {
 		agg.OnSomethingHappened(e.(domain.SomethingHappened)) //nolint:forcetypeassert
+
+		return nil
 	})
 
 	return eventApplier
@@ -191,3 +319,55 @@ func createCommandHandler(agg *domain.TestAggregate) *aggregate.CommandHandler {
 
 	return commandHandler
 }
+
+// countingAggregate has event appliers which return values other than an error.
+type countingAggregate struct {
+	id    cqrs.Identifier
+	count int
+}
+
+func (a *countingAggregate) AggregateID() cqrs.Identifier {
+	return a.id
+}
+
+func (a *countingAggregate) AggregateType() string {
+	return "mock.CountingAggregate"
+}
+
+func (a *countingAggregate) OnSomethingHappened(_ domain.SomethingHappened) bool {
+	a.count++
+
+	return true
+}
+
+func (a *countingAggregate) OnSomethingElseHappened(_ domain.SomethingElseHappened) int {
+	a.count++
+
+	return a.count
+}
+
+// batchAggregate produces a batch of events the second of which cannot be applied.
+type batchAggregate struct {
+	id      cqrs.Identifier
+	applied int
+}
+
+func (a *batchAggregate) AggregateID() cqrs.Identifier {
+	return a.id
+}
+
+func (a *batchAggregate) AggregateType() string {
+	return "mock.BatchAggregate"
+}
+
+func (a *batchAggregate) MakeSomethingHappen(_ domain.MakeSomethingHappen) ([]cqrs.DomainEvent, error) {
+	return []cqrs.DomainEvent{domain.SomethingHappened{}, domain.SomethingImpossibleHappened{}}, nil
+}
+
+func (a *batchAggregate) OnSomethingHappened(_ domain.SomethingHappened) {
+	a.applied++
+}
+
+func (a *batchAggregate) OnSomethingImpossibleHappened(_ domain.SomethingImpossibleHappened) error {
+	return domain.ErrImpossibleTransition
+}
@@ -96,3 +96,58 @@ func Example() {
 	fmt.Printf("Produced events: %v\n", events)
 	// Output: Produced events: [{123 true}]
 }
+
+// Counted is a user-defined event produced by the counter decider.
+type Counted struct {
+	By int
+}
+
+func (e Counted) EventType() string {
+	return "Counted"
+}
+
+// Count is a user-defined command handled by the counter decider.
+type Count struct {
+	ID MyIdentifier
+	By int
+}
+
+func (c Count) AggregateID() cqrs.Identifier {
+	return c.ID
+}
+
+func (c Count) AggregateType() string {
+	return "Counter"
+}
+
+func (c Count) CommandType() string {
+	return "Count"
+}
+
+// ExampleFromDecider demonstrates a functional aggregate built of pure functions.
+func ExampleFromDecider() {
+	counter := aggregate.Decider[int, Count, Counted]{
+		Decide: func(total int, c Count) ([]Counted, error) {
+			return []Counted{{By: c.By}}, nil
+		},
+		Evolve: func(total int, e Counted) int {
+			return total + e.By
+		},
+		Initial: func() int {
+			return 0
+		},
+	}
+
+	id := MyIdentifier("123")
+	esAgg := aggregate.FromDecider(id, "Counter", counter)
+
+	events, err := esAgg.Handle(Count{ID: id, By: 2})
+	if err != nil {
+		fmt.Printf("an error occurred: %v\n", err)
+
+		return
+	}
+
+	fmt.Printf("Produced events: %v\n", events)
+	// Output: Produced events: [{2}]
+}
@@ -10,13 +10,17 @@ import (
 // It maintains a registry of factory functions for different aggregate types.
 type Factory struct {
 	factories map[string]cqrs.FactoryFn
+	opts      []Option
 }
 
 // NewFactory creates a new instance of Factory and initializes its internal factory registry.
+//
+// The given options are applied to the aggregates converted with Factory.FromAggregate.
 // It returns a pointer to the created Factory instance.
-func NewFactory() *Factory {
+func NewFactory(opts ...Option) *Factory {
 	return &Factory{
 		factories: make(map[string]cqrs.FactoryFn),
+		opts:      opts,
 	}
 }
 
@@ -37,18 +41,55 @@ func (f *Factory) CreateAggregate(aggregateType string, id cqrs.Identifier) (cqr
 		return nil, fmt.Errorf("%w: %s", ErrAggregateNotRegistered, aggregateType)
 	}
 
-	return factory(id), nil
+	agg := factory(id)
+
+	// the factory function provides the fresh instances the state is rebuilt from, see EventSourced.Handle.
+	if es, ok := agg.(*EventSourced); ok && es.fresh == nil {
+		es.fresh = func() cqrs.Aggregate {
+			if fresh, ok := factory(id).(*EventSourced); ok {
+				return fresh.Aggregate
+			}
+
+			return nil
+		}
+	}
+
+	return agg, nil
+}
+
+// FromAggregate converts a cqrs.Aggregate using the options the factory was created with.
+//
+// Example:
+//
+//	f := aggregate.NewFactory(aggregate.WithNaming(naming))
+//	f.RegisterAggregate("account.Aggregate", func(ID cqrs.Identifier) cqrs.ESAggregate {
+//		return f.FromAggregate(account.NewAggregate(ID))
+//	})
+func (f *Factory) FromAggregate(agg cqrs.Aggregate) *EventSourced {
+	return FromAggregate(agg, f.opts...)
 }
 
 // FromAggregate takes a cqrs.Aggregate and returns a cqrs.ESAggregate.
 //
 // It automatically registers all the command handlers and event appliers found in the aggregate.
-func FromAggregate(agg cqrs.Aggregate) *EventSourced {
-	handler := NewCommandHandler()
+func FromAggregate(agg cqrs.Aggregate, opts ...Option) *EventSourced {
+	handler := NewCommandHandler(opts...)
 	handler.RegisterHandlers(agg)
 
-	eventApplier := NewEventApplier()
+	eventApplier := NewEventApplier(opts...)
 	eventApplier.RegisterAppliers(agg)
 
 	return New(agg, handler, eventApplier)
 }
+
+// FromAggregateStrict takes a cqrs.Aggregate and returns a cqrs.ESAggregate.
+//
+// Unlike FromAggregate it verifies the aggregate first and returns a *ConventionError
+// describing every malformed command handler and event applier found.
+func FromAggregateStrict(agg cqrs.Aggregate, opts ...Option) (*EventSourced, error) {
+	if err := Verify(agg, opts...); err != nil {
+		return nil, err
+	}
+
+	return FromAggregate(agg, opts...), nil
+}
@@ -61,4 +61,34 @@ func TestFactory(t *testing.T) {
 		// assert
 		assert.Implements(t, (*cqrs.ESAggregate)(nil), esAgg)
 	})
+
+	t.Run("it binds the cached handlers to each aggregate instance", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		happened := aggregate.FromAggregate(aggtest.NewTestAggregate(aggtest.StringIdentifier(faker.UUIDHyphenated())))
+		fresh := aggregate.FromAggregate(aggtest.NewTestAggregate(aggtest.StringIdentifier(faker.UUIDHyphenated())))
+
+		err := happened.Apply(aggtest.SomethingHappened{})
+		assert.NoError(t, err)
+
+		// act
+		_, happenedErr := happened.Handle(aggtest.MakeSomethingHappen{})
+		events, freshErr := fresh.Handle(aggtest.MakeSomethingHappen{})
+
+		// assert
+		assert.ErrorIs(t, happenedErr, aggtest.ErrItCanHappenOnceOnly)
+		assert.NoError(t, freshErr)
+		assert.Equal(t, []cqrs.DomainEvent{aggtest.SomethingHappened{}}, events)
+	})
+}
+
+func BenchmarkFromAggregate(b *testing.B) {
+	id := aggtest.StringIdentifier(faker.UUIDHyphenated())
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		aggregate.FromAggregate(aggtest.NewTestAggregate(id))
+	}
 }
@@ -0,0 +1,79 @@
+package aggregate
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/screwyprof/cqrs/naming"
+)
+
+// DefaultNaming is the naming convention used unless another one is configured.
+//
+// Command handlers are named exactly after cqrs.Command.CommandType(),
+// event appliers are named "On" + cqrs.DomainEvent.EventType().
+var DefaultNaming = naming.Default //nolint:gochecknoglobals
+
+// Naming is a strategy which routes commands and events to the aggregate methods handling them.
+//
+// It is an alias of naming.Naming, which is shared with the event handlers.
+type Naming = naming.Naming
+
+// MethodNaming routes commands and events to the methods named after their types, see naming.MethodNaming.
+type MethodNaming = naming.MethodNaming
+
+// ParameterTypeNaming routes commands and events by the Go type of the method parameter,
+// see naming.ParameterTypeNaming.
+type ParameterTypeNaming = naming.ParameterTypeNaming
+
+// CamelCase converts a command or an event type which may contain dots, dashes or versions to a method name.
+//
+// For example "account.money_deposited.v2" becomes "AccountMoneyDepositedV2".
+func CamelCase(messageType string) string {
+	return naming.CamelCase(messageType)
+}
+
+// keyedMethod is an aggregate method along with the routing key it is registered under.
+type keyedMethod struct {
+	key    string
+	method reflect.Method
+}
+
+// accepts checks that the message routed to the method is of the type the method accepts.
+//
+// It returns ErrTypeCollision if a message of another type shares the routing key.
+func (m keyedMethod) accepts(message any) error {
+	if m.method.Type.NumIn() != 2 {
+		return nil
+	}
+
+	messageType := reflect.TypeOf(message)
+	if paramType := m.method.Type.In(1); !messageType.AssignableTo(paramType) {
+		return fmt.Errorf("%w: %s routed to %s which accepts %s",
+			ErrTypeCollision, naming.TypeKey(messageType), m.method.Name, naming.TypeKey(paramType))
+	}
+
+	return nil
+}
+
+// Option configures the way aggregates are turned into event sourced aggregates.
+type Option func(*options)
+
+type options struct {
+	naming Naming
+}
+
+// WithNaming sets the naming convention used to route commands and events.
+func WithNaming(naming Naming) Option {
+	return func(o *options) {
+		o.naming = naming
+	}
+}
+
+func newOptions(opts ...Option) *options {
+	config := &options{naming: DefaultNaming}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	return config
+}
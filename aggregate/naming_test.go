@@ -0,0 +1,167 @@
+package aggregate_test
+
+import (
+	"testing"
+
+	"github.com/go-faker/faker/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/cqrs"
+	"github.com/screwyprof/cqrs/aggregate"
+	"github.com/screwyprof/cqrs/aggregate/aggtest"
+)
+
+func TestMethodNaming(t *testing.T) {
+	t.Parallel()
+
+	naming := &aggregate.MethodNaming{
+		CommandHandlerPrefix: "Handle",
+		EventApplierPrefix:   "Apply",
+		MethodName:           aggregate.CamelCase,
+	}
+
+	t.Run("it routes commands and events to the prefixed methods", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		agg := aggtest.NewPrefixedAggregate(aggtest.StringIdentifier(faker.UUIDHyphenated()))
+		esAgg := aggregate.FromAggregate(agg, aggregate.WithNaming(naming))
+
+		// act
+		events, err := esAgg.Handle(aggtest.MakeSomethingHappen{})
+		assert.NoError(t, err)
+
+		err = esAgg.Apply(aggtest.SomethingHappened{Data: "v1"})
+		assert.NoError(t, err)
+
+		// assert
+		assert.Equal(t, []cqrs.DomainEvent{aggtest.SomethingHappenedV2{Data: "v2"}}, events)
+		assert.Equal(t, []string{"v2", "v1"}, agg.Happened)
+	})
+
+	t.Run("it routes with the naming allocated per aggregate instance", func(t *testing.T) {
+		t.Parallel()
+
+		for range 3 {
+			// arrange
+			agg := aggtest.NewPrefixedAggregate(aggtest.StringIdentifier(faker.UUIDHyphenated()))
+			esAgg := aggregate.FromAggregate(agg, aggregate.WithNaming(&aggregate.MethodNaming{
+				CommandHandlerPrefix: "Handle",
+				EventApplierPrefix:   "Apply",
+				MethodName:           aggregate.CamelCase,
+			}))
+
+			// act
+			events, err := esAgg.Handle(aggtest.MakeSomethingHappen{})
+
+			// assert
+			assert.NoError(t, err)
+			assert.Equal(t, []cqrs.DomainEvent{aggtest.SomethingHappenedV2{Data: "v2"}}, events)
+		}
+	})
+
+	t.Run("it applies the factory naming to the converted aggregates", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+
+		f := aggregate.NewFactory(aggregate.WithNaming(naming))
+		f.RegisterAggregate(aggtest.PrefixedAggregateType, func(ID cqrs.Identifier) cqrs.ESAggregate {
+			return f.FromAggregate(aggtest.NewPrefixedAggregate(ID))
+		})
+
+		agg, err := f.CreateAggregate(aggtest.PrefixedAggregateType, ID)
+		assert.NoError(t, err)
+
+		// act
+		events, err := agg.Handle(aggtest.MakeSomethingHappen{AggID: ID})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, []cqrs.DomainEvent{aggtest.SomethingHappenedV2{Data: "v2"}}, events)
+	})
+
+	t.Run("it verifies the aggregate against the given naming", func(t *testing.T) {
+		t.Parallel()
+
+		agg := aggtest.NewPrefixedAggregate(aggtest.StringIdentifier(faker.UUIDHyphenated()))
+
+		assert.NoError(t, aggregate.Verify(agg, aggregate.WithNaming(naming)))
+		assert.ErrorIs(t, aggregate.Verify(agg), aggregate.ErrConventionViolation)
+	})
+
+	t.Run("it does not find handlers which do not follow the default naming", func(t *testing.T) {
+		t.Parallel()
+
+		esAgg := aggregate.FromAggregate(aggtest.NewPrefixedAggregate(aggtest.StringIdentifier(faker.UUIDHyphenated())))
+
+		_, err := esAgg.Handle(aggtest.MakeSomethingHappen{})
+
+		assert.ErrorIs(t, err, aggregate.ErrCommandHandlerNotFound)
+	})
+}
+
+func TestParameterTypeNaming(t *testing.T) {
+	t.Parallel()
+
+	naming := aggregate.WithNaming(aggregate.ParameterTypeNaming{})
+
+	t.Run("it routes commands and events by their Go type", func(t *testing.T) {
+		t.Parallel()
+
+		esAgg := aggregate.FromAggregate(aggtest.NewTestAggregate(aggtest.StringIdentifier(faker.UUIDHyphenated())), naming)
+
+		events, err := esAgg.Handle(aggtest.MakeSomethingHappen{})
+		assert.NoError(t, err)
+		assert.Equal(t, []cqrs.DomainEvent{aggtest.SomethingHappened{}}, events)
+
+		assert.NoError(t, esAgg.Apply(aggtest.SomethingHappened{}))
+	})
+
+	t.Run("it does not route commands and events of other types sharing the type string", func(t *testing.T) {
+		t.Parallel()
+
+		esAgg := aggregate.FromAggregate(aggtest.NewTestAggregate(aggtest.StringIdentifier(faker.UUIDHyphenated())), naming)
+
+		_, err := esAgg.Handle(aggtest.MakeSomethingHappenElsewhere{})
+		assert.ErrorIs(t, err, aggregate.ErrCommandHandlerNotFound)
+
+		err = esAgg.Apply(aggtest.SomethingHappenedElsewhere{})
+		assert.ErrorIs(t, err, aggregate.ErrEventApplierNotFound)
+	})
+
+	t.Run("it reports methods handling the same type", func(t *testing.T) {
+		t.Parallel()
+
+		err := aggregate.Verify(aggtest.NewMalformedAggregate(aggtest.StringIdentifier(faker.UUIDHyphenated())), naming)
+
+		var conventionErr *aggregate.ConventionError
+		assert.ErrorAs(t, err, &conventionErr)
+		assert.Contains(t, violatingMethods(conventionErr), "MakeSomethingHappen, MakeSomethingHappenAgain")
+	})
+}
+
+func TestTypeCollision(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it fails to handle a command of another type sharing the command type", func(t *testing.T) {
+		t.Parallel()
+
+		esAgg := aggregate.FromAggregate(aggtest.NewTestAggregate(aggtest.StringIdentifier(faker.UUIDHyphenated())))
+
+		_, err := esAgg.Handle(aggtest.MakeSomethingHappenElsewhere{})
+
+		assert.ErrorIs(t, err, aggregate.ErrTypeCollision)
+	})
+
+	t.Run("it fails to apply an event of another type sharing the event type", func(t *testing.T) {
+		t.Parallel()
+
+		esAgg := aggregate.FromAggregate(aggtest.NewTestAggregate(aggtest.StringIdentifier(faker.UUIDHyphenated())))
+
+		err := esAgg.Apply(aggtest.SomethingHappenedElsewhere{})
+
+		assert.ErrorIs(t, err, aggregate.ErrTypeCollision)
+	})
+}
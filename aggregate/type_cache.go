@@ -0,0 +1,50 @@
+package aggregate
+
+import (
+	"reflect"
+	"sync"
+)
+
+// typeCache memoizes metadata computed with reflection per Go type and naming convention.
+//
+// It keeps a single entry per Go type and type of the naming convention, the entry is reused
+// while the same naming value is given, so its size is bounded by the number of the types
+// no matter how many naming values are allocated.
+// The metadata is computed on every call if the naming value is not comparable.
+//
+// It is safe for concurrent use.
+type typeCache[T any] struct {
+	entries sync.Map
+}
+
+// typeCacheKey identifies the entry of a Go type computed with a naming convention of the given type.
+type typeCacheKey struct {
+	t      reflect.Type
+	naming reflect.Type
+}
+
+// typeCacheEntry is the metadata along with the naming value it has been computed with.
+type typeCacheEntry[T any] struct {
+	naming Naming
+	value  T
+}
+
+// load returns the cached metadata for the given type and naming computing it if the naming has changed.
+func (c *typeCache[T]) load(t reflect.Type, naming Naming, compute func() T) T {
+	if !reflect.ValueOf(naming).Comparable() {
+		return compute()
+	}
+
+	key := typeCacheKey{t: t, naming: reflect.TypeOf(naming)}
+
+	if cached, ok := c.entries.Load(key); ok {
+		if entry := cached.(typeCacheEntry[T]); entry.naming == naming { //nolint:forcetypeassert
+			return entry.value
+		}
+	}
+
+	value := compute()
+	c.entries.Store(key, typeCacheEntry[T]{naming: naming, value: value})
+
+	return value
+}
@@ -40,7 +40,9 @@ type EventApplier interface {
 }
 
 // EventApplierFunc is a function type that can be used as an event applier.
-type EventApplierFunc func(DomainEvent)
+//
+// It returns an error if the event cannot be applied, e.g. the history is corrupt.
+type EventApplierFunc func(DomainEvent) error
 
 // Aggregate represents a cluster of related objects that can be treated as a single unit.
 //
@@ -75,3 +77,21 @@ type AggregateFactory interface {
 	RegisterAggregate(aggregateType string, factory FactoryFn)
 	CreateAggregate(aggregateType string, ID Identifier) (ESAggregate, error)
 }
+
+// Query asks for data without changing the state of the system.
+//
+// Queries are named after the data they ask for, e.g., GetAccountDetails.
+type Query interface {
+	QueryType() string
+}
+
+// QueryHandler is responsible for answering queries.
+//
+// It returns the result of the query on success.
+// It returns an error if the query cannot be answered.
+type QueryHandler interface {
+	Handle(q Query) (any, error)
+}
+
+// QueryHandlerFunc is a function type that can be used as a query handler.
+type QueryHandlerFunc func(Query) (any, error)
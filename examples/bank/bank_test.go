@@ -19,6 +19,7 @@ import (
 	"github.com/screwyprof/cqrs/x/eventbus"
 	"github.com/screwyprof/cqrs/x/eventhandler"
 	"github.com/screwyprof/cqrs/x/eventstore"
+	"github.com/screwyprof/cqrs/x/querybus"
 )
 
 func Example() {
@@ -34,7 +35,7 @@ func Example() {
 	failCommandOnError(d.Handle(command.WithdrawMoney{ID: ID, Amount: 100}))
 	failCommandOnError(d.Handle(command.DepositMoney{ID: ID, Amount: 500}))
 
-	printer := ui.NewConsolePrinter(os.Stdout, accountReporter)
+	printer := ui.NewConsolePrinter(os.Stdout, createQueryBus(accountReporter))
 	failOnError(printer.PrintAccountStatement(ID))
 
 	// Output:
@@ -63,6 +64,13 @@ func createDispatcher(accountReporter eh.AccountReporting) *dispatcher.Dispatche
 	return dispatcher.NewDispatcher(aggregateStore)
 }
 
+func createQueryBus(accountReporter eh.AccountReporting) *querybus.InMemoryQueryBus {
+	queryBus := querybus.NewInMemoryQueryBus()
+	reporting.NewAccountQueries(accountReporter).RegisterHandlers(queryBus)
+
+	return queryBus
+}
+
 func createAggregate(ID cqrs.Identifier) cqrs.ESAggregate {
 	acc := account.NewAggregate(ID)
 
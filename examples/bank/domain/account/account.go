@@ -36,6 +36,11 @@ func (a *Aggregate) AggregateType() string {
 	return "account.Aggregate"
 }
 
+// Balance returns the current balance of the account.
+func (a *Aggregate) Balance() int64 {
+	return a.balance
+}
+
 // OpenAccount opens a new account with a given number.
 func (a *Aggregate) OpenAccount(c command.OpenAccount) ([]domain.Event, error) {
 	return []domain.Event{event.AccountOpened{ID: c.ID, Number: c.Number}}, nil
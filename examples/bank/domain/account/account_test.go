@@ -1,6 +1,10 @@
 package account_test
 
 import (
+	"flag"
+	"fmt"
+	"math/rand/v2"
+	"os"
 	"testing"
 
 	"github.com/go-faker/faker/v4"
@@ -9,6 +13,8 @@ import (
 	"github.com/screwyprof/cqrs"
 	"github.com/screwyprof/cqrs/aggregate"
 	"github.com/screwyprof/cqrs/aggregate/aggtest"
+	"github.com/screwyprof/cqrs/aggregate/aggtest/proptest"
+	"github.com/screwyprof/cqrs/aggregate/aggtest/specdoc"
 	. "github.com/screwyprof/cqrs/aggregate/aggtest/testdsl"
 	"github.com/screwyprof/cqrs/examples/bank/domain"
 	"github.com/screwyprof/cqrs/examples/bank/domain/account"
@@ -19,6 +25,28 @@ import (
 // ensure that the account aggregate implements cqrs.Aggregate interface.
 var _ cqrs.Aggregate = (*account.Aggregate)(nil)
 
+//nolint:gochecknoglobals
+var (
+	specification = specdoc.NewReporter()
+	specdocPath   = flag.String("specdoc", "", "write the account specification to the given .md or .html file")
+)
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+
+	code := m.Run()
+
+	if *specdocPath != "" {
+		if err := specification.WriteFile(*specdocPath); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+
+			code = 1
+		}
+	}
+
+	os.Exit(code)
+}
+
 func TestAggregate(t *testing.T) {
 	t.Run("panics if ID is not given", func(t *testing.T) {
 		t.Parallel()
@@ -48,13 +76,21 @@ func TestAggregate(t *testing.T) {
 		assert.Equal(t, "account.Aggregate", agg.AggregateType())
 	})
 
+	t.Run("follows the aggregate conventions", func(t *testing.T) {
+		t.Parallel()
+
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+
+		assert.NoError(t, aggregate.Verify(account.NewAggregate(ID)))
+	})
+
 	t.Run("opens an account", func(t *testing.T) {
 		t.Parallel()
 
 		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
 		number := faker.Word()
 
-		Test(t)(
+		Test(t, WithReporter(specification))(
 			Given(createTestAggregate(ID)),
 			When(command.OpenAccount{ID: ID, Number: number}),
 			Then(event.AccountOpened{ID: ID, Number: number}),
@@ -68,7 +104,7 @@ func TestAggregate(t *testing.T) {
 		number := faker.Word()
 		amount := faker.UnixTime()
 
-		Test(t)(
+		Test(t, WithReporter(specification))(
 			Given(createTestAggregate(ID), event.AccountOpened{ID: ID, Number: number}),
 			When(command.DepositMoney{ID: ID, Amount: amount}),
 			Then(event.MoneyDeposited{ID: ID, Amount: amount, Balance: amount}),
@@ -85,7 +121,7 @@ func TestAggregate(t *testing.T) {
 		amount := faker.UnixTime()
 		newBalance := amount + currentBalance
 
-		Test(t)(
+		Test(t, WithReporter(specification))(
 			Given(createTestAggregate(ID),
 				event.AccountOpened{ID: ID, Number: number},
 				event.MoneyDeposited{ID: ID, Amount: currentBalance, Balance: currentBalance},
@@ -105,7 +141,7 @@ func TestAggregate(t *testing.T) {
 		amount := int64(100)
 		newBalance := currentBalance - amount
 
-		Test(t)(
+		Test(t, WithReporter(specification))(
 			Given(createTestAggregate(ID),
 				event.AccountOpened{ID: ID, Number: number},
 				event.MoneyDeposited{ID: ID, Amount: currentBalance, Balance: currentBalance}),
@@ -121,7 +157,7 @@ func TestAggregate(t *testing.T) {
 		number := faker.Word()
 		amount := faker.UnixTime()
 
-		Test(t)(
+		Test(t, WithReporter(specification))(
 			Given(createTestAggregate(ID), event.AccountOpened{ID: ID, Number: number}),
 			When(command.WithdrawMoney{ID: ID, Amount: amount}),
 			ThenFailWith(account.ErrBalanceIsNotHighEnough),
@@ -129,8 +165,79 @@ func TestAggregate(t *testing.T) {
 	})
 }
 
+func TestAggregateEventSchema(t *testing.T) {
+	t.Parallel()
+
+	// the values are fixed, so that the recorded events change only when the event schema does.
+	ID := aggtest.StringIdentifier("5ad5c2f3-7a4a-4f0e-9d7c-2f0b8a3b1c11")
+	number := "ACC777"
+
+	t.Run("account opened", func(t *testing.T) {
+		t.Parallel()
+
+		Test(t, WithReporter(specification))(
+			Given(createTestAggregate(ID)),
+			When(command.OpenAccount{ID: ID, Number: number}),
+			ThenGolden("account_opened"),
+		)
+	})
+
+	t.Run("money deposited", func(t *testing.T) {
+		t.Parallel()
+
+		Test(t, WithReporter(specification))(
+			Given(createTestAggregate(ID), event.AccountOpened{ID: ID, Number: number}),
+			When(command.DepositMoney{ID: ID, Amount: 500}),
+			ThenGolden("money_deposited"),
+		)
+	})
+
+	t.Run("money withdrawn", func(t *testing.T) {
+		t.Parallel()
+
+		Test(t, WithReporter(specification))(
+			Given(createTestAggregate(ID),
+				event.AccountOpened{ID: ID, Number: number},
+				event.MoneyDeposited{ID: ID, Amount: 500, Balance: 500},
+			),
+			When(command.WithdrawMoney{ID: ID, Amount: 200}),
+			ThenGolden("money_withdrawn"),
+		)
+	})
+}
+
 func createTestAggregate(ID domain.Identifier) *aggregate.EventSourced {
 	accAgg := account.NewAggregate(ID)
 
 	return aggregate.FromAggregate(accAgg)
 }
+
+func TestAggregateProperties(t *testing.T) {
+	t.Parallel()
+
+	proptest.Check(t, proptest.Property[*account.Aggregate]{
+		New: func(ID cqrs.Identifier) *account.Aggregate {
+			return account.NewAggregate(ID)
+		},
+		Commands: []proptest.Generator{
+			func(rnd *rand.Rand, ID cqrs.Identifier) cqrs.Command {
+				return command.OpenAccount{ID: ID, Number: fmt.Sprintf("ACC%06d", rnd.IntN(1000000))}
+			},
+			func(rnd *rand.Rand, ID cqrs.Identifier) cqrs.Command {
+				return command.DepositMoney{ID: ID, Amount: 1 + rnd.Int64N(1000)}
+			},
+			func(rnd *rand.Rand, ID cqrs.Identifier) cqrs.Command {
+				return command.WithdrawMoney{ID: ID, Amount: 1 + rnd.Int64N(1000)}
+			},
+		},
+		Invariants: []proptest.Invariant[*account.Aggregate]{
+			func(acc *account.Aggregate) error {
+				if acc.Balance() < 0 {
+					return fmt.Errorf("balance %d is negative", acc.Balance())
+				}
+
+				return nil
+			},
+		},
+	})
+}
@@ -1,23 +1,23 @@
 package eventhandler_test
 
 import (
-	"errors"
 	"testing"
 
 	"github.com/go-faker/faker/v4"
 	"github.com/stretchr/testify/assert"
-	m "github.com/stretchr/testify/mock"
 
 	"github.com/screwyprof/cqrs/aggregate/aggtest"
 	"github.com/screwyprof/cqrs/examples/bank/domain/event"
 	eh "github.com/screwyprof/cqrs/examples/bank/eventhandler"
 	"github.com/screwyprof/cqrs/examples/bank/report"
+	"github.com/screwyprof/cqrs/examples/bank/reporting"
 	"github.com/screwyprof/cqrs/x/eventhandler"
+	. "github.com/screwyprof/cqrs/x/eventhandler/testdsl"
 )
 
 func TestNewAccountDetailsProjector(t *testing.T) {
 	t.Run("ItCreatesNewInstance", func(t *testing.T) {
-		projector := eh.NewAccountDetailsProjector(&accountReporterMock{})
+		projector := eh.NewAccountDetailsProjector(reporting.NewInMemoryAccountReporter())
 		assert.True(t, projector != nil)
 	})
 
@@ -31,148 +31,97 @@ func TestNewAccountDetailsProjector(t *testing.T) {
 
 func TestAccountDetailsProjector(t *testing.T) {
 	t.Run("ItProjectsAccountOpenedEvent", func(t *testing.T) {
-		// arrange
 		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
 		number := faker.Word()
 
-		want := &report.Account{
-			ID:     ID,
-			Number: number,
-		}
-
-		accountReporter := &accountReporterMock{}
-		accountReporter.On("Save", want)
-
-		accountProjector := eventhandler.New()
-		accountProjector.RegisterHandlers(eh.NewAccountDetailsProjector(accountReporter))
-
-		// act
-		err := accountProjector.Handle(event.AccountOpened{ID: ID, Number: number})
-
-		// assert
-		assert.NoError(t, err)
-		accountReporter.AssertCalled(t, "Save", want)
+		accountReporter := reporting.NewInMemoryAccountReporter()
+		Test(t, createAccountProjector(accountReporter), accountReporter)(
+			Given(),
+			When(event.AccountOpened{ID: ID, Number: number}),
+			Then(func(accountReporter *reporting.InMemoryAccountReporter) {
+				assertAccount(t, accountReporter, &report.Account{ID: ID, Number: number})
+			}),
+		)
 	})
 
 	t.Run("ItProjectsMoneyDepositedEvent", func(t *testing.T) {
-		// arrange
 		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
-		amount := faker.UnixTime()
-		balance := faker.UnixTime()
-
-		accountReporter := createAccountReporterMock(eh.Ledger{
-			ID:      ID,
-			Action:  "deposit",
-			Amount:  amount,
-			Balance: balance,
-		})
-
-		accountProjector := eventhandler.New()
-		accountProjector.RegisterHandlers(eh.NewAccountDetailsProjector(accountReporter))
-
-		// act
-		err := accountProjector.Handle(event.MoneyDeposited{ID: ID, Amount: amount, Balance: balance})
+		number := faker.Word()
 
-		// assert
-		assert.NoError(t, err)
-		accountReporter.AssertExpectations(t)
+		accountReporter := reporting.NewInMemoryAccountReporter()
+		Test(t, createAccountProjector(accountReporter), accountReporter)(
+			Given(event.AccountOpened{ID: ID, Number: number}),
+			When(event.MoneyDeposited{ID: ID, Amount: 100, Balance: 100}),
+			Then(func(accountReporter *reporting.InMemoryAccountReporter) {
+				assertAccount(t, accountReporter, &report.Account{
+					ID:      ID,
+					Number:  number,
+					Balance: 100,
+					Ledgers: []report.Ledger{{Action: "deposit", Amount: 100, Balance: 100}},
+				})
+			}),
+		)
 	})
 
 	t.Run("ItReturnsAnErrorWhenItCannotProjectMoneyDepositedEvent", func(t *testing.T) {
-		// arrange
 		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
-		want := errors.New("an error occurred")
-
-		accountReporter := createAccountReporterMockWithError(ID, want)
 
-		accountProjector := eventhandler.New()
-		accountProjector.RegisterHandlers(eh.NewAccountDetailsProjector(accountReporter))
-
-		// act
-		err := accountProjector.Handle(
-			event.MoneyDeposited{ID: ID, Amount: faker.UnixTime(), Balance: faker.UnixTime()})
-
-		// assert
-		assert.Equal(t, want, err)
-		accountReporter.AssertExpectations(t)
+		accountReporter := reporting.NewInMemoryAccountReporter()
+		Test(t, createAccountProjector(accountReporter), accountReporter)(
+			Given(),
+			When(event.MoneyDeposited{ID: ID, Amount: faker.UnixTime(), Balance: faker.UnixTime()}),
+			ThenFailWith(reporting.ErrAccountNotFound),
+		)
 	})
 
 	t.Run("ItProjectsMoneyWithdrawnEvent", func(t *testing.T) {
-		// arrange
 		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
-		amount := faker.UnixTime()
-		balance := faker.UnixTime()
-
-		accountReporter := createAccountReporterMock(eh.Ledger{
-			ID:      ID,
-			Action:  "withdraw",
-			Amount:  amount,
-			Balance: balance,
-		})
-
-		accountProjector := eventhandler.New()
-		accountProjector.RegisterHandlers(eh.NewAccountDetailsProjector(accountReporter))
-
-		// act
-		err := accountProjector.Handle(event.MoneyWithdrawn{ID: ID, Amount: amount, Balance: balance})
+		number := faker.Word()
 
-		// assert
-		assert.NoError(t, err)
-		accountReporter.AssertExpectations(t)
+		accountReporter := reporting.NewInMemoryAccountReporter()
+		Test(t, createAccountProjector(accountReporter), accountReporter)(
+			Given(
+				event.AccountOpened{ID: ID, Number: number},
+				event.MoneyDeposited{ID: ID, Amount: 100, Balance: 100},
+			),
+			When(event.MoneyWithdrawn{ID: ID, Amount: 40, Balance: 60}),
+			Then(func(accountReporter *reporting.InMemoryAccountReporter) {
+				assertAccount(t, accountReporter, &report.Account{
+					ID:      ID,
+					Number:  number,
+					Balance: 60,
+					Ledgers: []report.Ledger{
+						{Action: "deposit", Amount: 100, Balance: 100},
+						{Action: "withdrawal", Amount: 40, Balance: 60},
+					},
+				})
+			}),
+		)
 	})
 
 	t.Run("ItReturnsAnErrorWhenItCannotProjectMoneyWithdrawnEvent", func(t *testing.T) {
-		// arrange
 		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
-		want := errors.New("an error occurred")
-
-		accountReporter := createAccountReporterMockWithError(ID, want)
-
-		accountProjector := eventhandler.New()
-		accountProjector.RegisterHandlers(eh.NewAccountDetailsProjector(accountReporter))
-
-		// act
-		err := accountProjector.Handle(
-			event.MoneyWithdrawn{ID: ID, Amount: faker.UnixTime(), Balance: faker.UnixTime()})
 
-		// assert
-		assert.Equal(t, want, err)
-		accountReporter.AssertExpectations(t)
+		accountReporter := reporting.NewInMemoryAccountReporter()
+		Test(t, createAccountProjector(accountReporter), accountReporter)(
+			Given(),
+			When(event.MoneyWithdrawn{ID: ID, Amount: faker.UnixTime(), Balance: faker.UnixTime()}),
+			ThenFailWith(reporting.ErrAccountNotFound),
+		)
 	})
 }
 
-func createAccountReporterMockWithError(ID report.Identifier, want error) *accountReporterMock {
-	var accountReport *report.Account
-	accountReporter := &accountReporterMock{}
-	accountReporter.On("AccountDetailsFor", ID).Return(accountReport, want)
-	return accountReporter
-}
-
-func createAccountReporterMock(l eh.Ledger) *accountReporterMock {
-	number := faker.Word()
-	want := &report.Account{
-		ID:     l.ID,
-		Number: number,
-	}
-	accountReporter := &accountReporterMock{}
-	accountReporter.On("AccountDetailsFor", l.ID).Return(want, nil)
-
-	want.Balance = l.Balance
-	want.Ledgers = append(want.Ledgers, report.Ledger{Action: l.Action, Amount: l.Amount})
-	accountReporter.On("Save", want)
+func createAccountProjector(accountReporter eh.AccountReporting) *eventhandler.EventHandler {
+	accountProjector := eventhandler.New()
+	accountProjector.RegisterHandlers(eh.NewAccountDetailsProjector(accountReporter))
 
-	return accountReporter
+	return accountProjector
 }
 
-type accountReporterMock struct {
-	m.Mock
-}
-
-func (r *accountReporterMock) AccountDetailsFor(ID report.Identifier) (*report.Account, error) {
-	args := r.Called(ID)
-	return args.Get(0).(*report.Account), args.Error(1)
-}
+func assertAccount(t *testing.T, accountReporter eh.GetAccountDetails, want *report.Account) {
+	t.Helper()
 
-func (r *accountReporterMock) Save(account *report.Account) {
-	r.Called(account)
+	got, err := accountReporter.AccountDetailsFor(want.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, want, got)
 }
@@ -0,0 +1,15 @@
+package query
+
+import "github.com/screwyprof/cqrs/examples/bank/report"
+
+// GetAccountDetails is a query for the details of an account.
+//
+// It is answered with *report.Account.
+type GetAccountDetails struct {
+	ID report.Identifier
+}
+
+// QueryType implements cqrs.Query interface.
+func (q GetAccountDetails) QueryType() string {
+	return "GetAccountDetails"
+}
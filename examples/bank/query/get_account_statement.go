@@ -0,0 +1,15 @@
+package query
+
+import "github.com/screwyprof/cqrs/examples/bank/report"
+
+// GetAccountStatement is a query for the statement of an account.
+//
+// It is answered with *report.Statement.
+type GetAccountStatement struct {
+	ID report.Identifier
+}
+
+// QueryType implements cqrs.Query interface.
+func (q GetAccountStatement) QueryType() string {
+	return "GetAccountStatement"
+}
@@ -0,0 +1,7 @@
+package report
+
+// Statement is an account statement.
+type Statement struct {
+	Number  string
+	Ledgers []Ledger
+}
@@ -0,0 +1,43 @@
+package reporting
+
+import (
+	"github.com/screwyprof/cqrs/examples/bank/eventhandler"
+	"github.com/screwyprof/cqrs/examples/bank/query"
+	"github.com/screwyprof/cqrs/examples/bank/report"
+	"github.com/screwyprof/cqrs/x/querybus"
+)
+
+// AccountQueries answers the account queries.
+type AccountQueries struct {
+	accountReporter eventhandler.GetAccountDetails
+}
+
+// NewAccountQueries creates a new instance of AccountQueries.
+func NewAccountQueries(accountReporter eventhandler.GetAccountDetails) *AccountQueries {
+	if accountReporter == nil {
+		panic("accountReporter is required")
+	}
+
+	return &AccountQueries{accountReporter: accountReporter}
+}
+
+// RegisterHandlers registers the account query handlers with the given query bus.
+func (r *AccountQueries) RegisterHandlers(b *querybus.InMemoryQueryBus) {
+	querybus.Register(b, r.AccountDetails)
+	querybus.Register(b, r.AccountStatement)
+}
+
+// AccountDetails answers GetAccountDetails query.
+func (r *AccountQueries) AccountDetails(q query.GetAccountDetails) (*report.Account, error) {
+	return r.accountReporter.AccountDetailsFor(q.ID)
+}
+
+// AccountStatement answers GetAccountStatement query.
+func (r *AccountQueries) AccountStatement(q query.GetAccountStatement) (*report.Statement, error) {
+	account, err := r.accountReporter.AccountDetailsFor(q.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &report.Statement{Number: account.Number, Ledgers: account.Ledgers}, nil
+}
@@ -0,0 +1,85 @@
+package reporting_test
+
+import (
+	"testing"
+
+	"github.com/go-faker/faker/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/cqrs/aggregate/aggtest"
+	"github.com/screwyprof/cqrs/examples/bank/query"
+	"github.com/screwyprof/cqrs/examples/bank/report"
+	"github.com/screwyprof/cqrs/examples/bank/reporting"
+	"github.com/screwyprof/cqrs/x/querybus"
+)
+
+func TestNewAccountQueries(t *testing.T) {
+	t.Run("ItPanicsIfAccountReporterIsNotGiven", func(t *testing.T) {
+		factory := func() {
+			reporting.NewAccountQueries(nil)
+		}
+		assert.Panics(t, factory)
+	})
+}
+
+func TestAccountQueries(t *testing.T) {
+	t.Run("ItAnswersGetAccountDetailsQuery", func(t *testing.T) {
+		// arrange
+		account := createAccount()
+		queryBus := createQueryBus(account)
+
+		// act
+		got, err := querybus.Ask[*report.Account](queryBus, query.GetAccountDetails{ID: account.ID})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, account, got)
+	})
+
+	t.Run("ItAnswersGetAccountStatementQuery", func(t *testing.T) {
+		// arrange
+		account := createAccount()
+		queryBus := createQueryBus(account)
+
+		// act
+		got, err := querybus.Ask[*report.Statement](queryBus, query.GetAccountStatement{ID: account.ID})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, &report.Statement{Number: account.Number, Ledgers: account.Ledgers}, got)
+	})
+
+	t.Run("ItFailsIfTheAccountIsNotFound", func(t *testing.T) {
+		// arrange
+		queryBus := createQueryBus(createAccount())
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+
+		// act
+		_, err := querybus.Ask[*report.Statement](queryBus, query.GetAccountStatement{ID: ID})
+
+		// assert
+		assert.ErrorIs(t, err, reporting.ErrAccountNotFound)
+	})
+}
+
+func createAccount() *report.Account {
+	return &report.Account{
+		ID:      aggtest.StringIdentifier(faker.UUIDHyphenated()),
+		Number:  faker.Word(),
+		Balance: 50,
+		Ledgers: []report.Ledger{
+			{Action: "deposit", Amount: 100, Balance: 100},
+			{Action: "withdrawal", Amount: 50, Balance: 50},
+		},
+	}
+}
+
+func createQueryBus(account *report.Account) *querybus.InMemoryQueryBus {
+	accountReporter := reporting.NewInMemoryAccountReporter()
+	accountReporter.Save(account)
+
+	queryBus := querybus.NewInMemoryQueryBus()
+	reporting.NewAccountQueries(accountReporter).RegisterHandlers(queryBus)
+
+	return queryBus
+}
@@ -1,42 +1,53 @@
 package reporting
 
 import (
+	"context"
 	"errors"
-	"sync"
 
 	"github.com/screwyprof/cqrs/examples/bank/report"
+	"github.com/screwyprof/cqrs/x/readmodel"
 )
 
 var ErrAccountNotFound = errors.New("account is not found")
 
 // InMemoryAccountReporter stores and retrieves account reports from memory.
 type InMemoryAccountReporter struct {
-	accounts  map[report.Identifier]*report.Account
-	accountMu sync.RWMutex
+	accounts *readmodel.Store[string, *report.Account]
 }
 
 // NewInMemoryAccountReporter creates a new instance of InMemoryAccountReporter.
 func NewInMemoryAccountReporter() *InMemoryAccountReporter {
 	return &InMemoryAccountReporter{
-		accounts: make(map[report.Identifier]*report.Account),
+		accounts: readmodel.New[string, *report.Account](),
 	}
 }
 
 // AccountDetailsFor implements report.GetAccountDetails interface.
 func (r *InMemoryAccountReporter) AccountDetailsFor(ID report.Identifier) (*report.Account, error) {
-	r.accountMu.RLock()
-	defer r.accountMu.RUnlock()
-
-	if acc, ok := r.accounts[ID]; ok {
-		return acc, nil
+	acc, err := r.accounts.Get(ID.String())
+	if errors.Is(err, readmodel.ErrNotFound) {
+		return nil, ErrAccountNotFound
 	}
 
-	return nil, ErrAccountNotFound
+	return acc, err
 }
 
 // Save implements AccountSaver.interface.
 func (r *InMemoryAccountReporter) Save(account *report.Account) {
-	r.accountMu.Lock()
-	defer r.accountMu.Unlock()
-	r.accounts[account.ID] = account
+	r.accounts.Put(account.ID.String(), account)
+}
+
+// SubscribeToAccountDetails sends the details of the account each time they change until the context is done.
+func (r *InMemoryAccountReporter) SubscribeToAccountDetails(
+	ctx context.Context, ID report.Identifier,
+) <-chan *report.Account {
+	return r.accounts.SubscribeTo(ctx, ID.String())
+}
+
+// SubscribeToAccounts sends the accounts matching the filter each time any of them changes
+// until the context is done, e.g. all the accounts with a negative balance.
+func (r *InMemoryAccountReporter) SubscribeToAccounts(
+	ctx context.Context, filter func(*report.Account) bool,
+) (<-chan readmodel.Page[*report.Account], error) {
+	return r.accounts.Subscribe(ctx, readmodel.Query[*report.Account]{Filter: filter})
 }
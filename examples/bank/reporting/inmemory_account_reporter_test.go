@@ -1,6 +1,7 @@
 package reporting_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/go-faker/faker/v4"
@@ -54,4 +55,52 @@ func TestInMemoryAccountReporter(t *testing.T) {
 
 		assert.Equal(t, reporting.ErrAccountNotFound, err)
 	})
+
+	t.Run("ItShouldNotShareTheStoredAccount", func(t *testing.T) {
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		accountReporter := reporting.NewInMemoryAccountReporter()
+		accountReporter.Save(&report.Account{ID: ID, Ledgers: []report.Ledger{{Action: "deposit", Amount: 100}}})
+
+		acc, err := accountReporter.AccountDetailsFor(ID)
+		assert.NoError(t, err)
+
+		acc.Ledgers[0].Amount = 200
+
+		got, err := accountReporter.AccountDetailsFor(ID)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(100), got.Ledgers[0].Amount)
+	})
+
+	t.Run("ItShouldSendAccountDetailsEachTimeTheyChange", func(t *testing.T) {
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		accountReporter := reporting.NewInMemoryAccountReporter()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		details := accountReporter.SubscribeToAccountDetails(ctx, ID)
+
+		accountReporter.Save(&report.Account{ID: ID, Balance: 100})
+		assert.Equal(t, int64(100), (<-details).Balance)
+
+		accountReporter.Save(&report.Account{ID: ID, Balance: 150})
+		assert.Equal(t, int64(150), (<-details).Balance)
+	})
+
+	t.Run("ItShouldSendAccountsMatchingTheFilterEachTimeTheyChange", func(t *testing.T) {
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		accountReporter := reporting.NewInMemoryAccountReporter()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		overdrawn, err := accountReporter.SubscribeToAccounts(ctx, func(acc *report.Account) bool {
+			return acc.Balance < 0
+		})
+		assert.NoError(t, err)
+		assert.Empty(t, (<-overdrawn).Items)
+
+		accountReporter.Save(&report.Account{ID: ID, Balance: -50})
+		assert.Len(t, (<-overdrawn).Items, 1)
+	})
 }
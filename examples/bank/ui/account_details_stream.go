@@ -0,0 +1,42 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/screwyprof/cqrs/examples/bank/report"
+	"github.com/screwyprof/cqrs/x/sse"
+)
+
+// ErrAccountIDRequired is returned when the account details are requested without the account id.
+var ErrAccountIDRequired = errors.New("account id is required")
+
+// AccountDetailsSubscriber subscribes to the changes of the account details.
+type AccountDetailsSubscriber interface {
+	SubscribeToAccountDetails(ctx context.Context, ID report.Identifier) <-chan *report.Account
+}
+
+// NewAccountDetailsStream creates an HTTP handler which streams the details of the account given by
+// the "id" query parameter as Server-Sent Events each time they change.
+func NewAccountDetailsStream(accounts AccountDetailsSubscriber) http.Handler {
+	if accounts == nil {
+		panic("accounts is required")
+	}
+
+	return sse.NewHandler(func(r *http.Request) (<-chan *report.Account, error) {
+		ID := r.URL.Query().Get("id")
+		if ID == "" {
+			return nil, ErrAccountIDRequired
+		}
+
+		return accounts.SubscribeToAccountDetails(r.Context(), accountID(ID)), nil
+	})
+}
+
+// accountID is an account identifier taken from a request.
+type accountID string
+
+func (id accountID) String() string {
+	return string(id)
+}
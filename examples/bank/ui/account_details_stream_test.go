@@ -0,0 +1,85 @@
+package ui_test
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-faker/faker/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/cqrs/aggregate/aggtest"
+	"github.com/screwyprof/cqrs/examples/bank/report"
+	"github.com/screwyprof/cqrs/examples/bank/reporting"
+	"github.com/screwyprof/cqrs/examples/bank/ui"
+)
+
+func TestNewAccountDetailsStream(t *testing.T) {
+	t.Run("ItPanicsIfAccountsAreNotGiven", func(t *testing.T) {
+		factory := func() {
+			ui.NewAccountDetailsStream(nil)
+		}
+		assert.Panics(t, factory)
+	})
+}
+
+func TestAccountDetailsStream(t *testing.T) {
+	t.Run("ItStreamsTheAccountDetailsEachTimeTheyChange", func(t *testing.T) {
+		// arrange
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+
+		accountReporter := reporting.NewInMemoryAccountReporter()
+		accountReporter.Save(&report.Account{ID: ID, Number: "ACC777"})
+
+		server := httptest.NewServer(ui.NewAccountDetailsStream(accountReporter))
+		defer server.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"?id="+ID.String(), nil)
+		assert.NoError(t, err)
+
+		// act
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+
+		defer resp.Body.Close()
+
+		events := bufio.NewScanner(resp.Body)
+		opened := nextEvent(events)
+
+		accountReporter.Save(&report.Account{ID: ID, Number: "ACC777", Balance: 100})
+		deposited := nextEvent(events)
+
+		// assert
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, opened, `"Balance":0`)
+		assert.Contains(t, deposited, `"Balance":100`)
+	})
+
+	t.Run("ItRespondsWithBadRequestIfTheAccountIDIsNotGiven", func(t *testing.T) {
+		// arrange
+		h := ui.NewAccountDetailsStream(reporting.NewInMemoryAccountReporter())
+		w := httptest.NewRecorder()
+
+		// act
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		// assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), ui.ErrAccountIDRequired.Error())
+	})
+}
+
+func nextEvent(events *bufio.Scanner) string {
+	for events.Scan() {
+		if line := events.Text(); line != "" {
+			return line
+		}
+	}
+
+	return ""
+}
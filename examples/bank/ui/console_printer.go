@@ -4,26 +4,28 @@ import (
 	"fmt"
 	"io"
 
-	"github.com/screwyprof/cqrs/examples/bank/eventhandler"
+	"github.com/screwyprof/cqrs"
+	"github.com/screwyprof/cqrs/examples/bank/query"
 	"github.com/screwyprof/cqrs/examples/bank/report"
+	"github.com/screwyprof/cqrs/x/querybus"
 )
 
 // ConsolePrinter prints account statement to console.
 type ConsolePrinter struct {
-	w               io.Writer
-	accountReporter eventhandler.GetAccountDetails
+	w       io.Writer
+	queries cqrs.QueryHandler
 }
 
 // NewConsolePrinter creates new instance of ConsolePrinter.
-func NewConsolePrinter(w io.Writer, accountReporter eventhandler.GetAccountDetails) *ConsolePrinter {
+func NewConsolePrinter(w io.Writer, queries cqrs.QueryHandler) *ConsolePrinter {
 	if w == nil {
 		panic("writer is required")
 	}
 
-	if accountReporter == nil {
-		panic("accountReporter is required")
+	if queries == nil {
+		panic("queries is required")
 	}
-	return &ConsolePrinter{w: w, accountReporter: accountReporter}
+	return &ConsolePrinter{w: w, queries: queries}
 }
 
 // PrintAccountStatement prints account statement to console.
@@ -35,15 +37,15 @@ func NewConsolePrinter(w io.Writer, accountReporter eventhandler.GetAccountDetai
 // 2 |  -100.00 |   900.00
 // 3 |   500.00 |  1400.00
 func (p *ConsolePrinter) PrintAccountStatement(ID report.Identifier) error {
-	account, err := p.accountReporter.AccountDetailsFor(ID)
+	statement, err := querybus.Ask[*report.Statement](p.queries, query.GetAccountStatement{ID: ID})
 	if err != nil {
 		return err
 	}
 
-	_, _ = fmt.Fprintf(p.w, "Account #%s:\n", account.Number)
+	_, _ = fmt.Fprintf(p.w, "Account #%s:\n", statement.Number)
 	_, _ = fmt.Fprintf(p.w, "%s |%9s | %8s\n", "#", "Amount", "Balance")
 
-	for idx, ledger := range account.Ledgers {
+	for idx, ledger := range statement.Ledgers {
 		_, _ = fmt.Fprint(p.w, report.FormatLedger(idx+1, ledger))
 	}
 
@@ -9,7 +9,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	m "github.com/stretchr/testify/mock"
 
+	"github.com/screwyprof/cqrs"
 	"github.com/screwyprof/cqrs/aggregate/aggtest"
+	"github.com/screwyprof/cqrs/examples/bank/query"
 	"github.com/screwyprof/cqrs/examples/bank/report"
 	"github.com/screwyprof/cqrs/examples/bank/ui"
 )
@@ -22,7 +24,7 @@ func TestNewConsolePrinter(t *testing.T) {
 		assert.Panics(t, factory)
 	})
 
-	t.Run("ItPanicsIfAccountReporterIsNotGiven", func(t *testing.T) {
+	t.Run("ItPanicsIfQueryHandlerIsNotGiven", func(t *testing.T) {
 		factory := func() {
 			ui.NewConsolePrinter(&bytes.Buffer{}, nil)
 		}
@@ -35,10 +37,8 @@ func TestConsolePrinter_PrintAccountStatement(t *testing.T) {
 		// arrange
 		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
 
-		accReport := &report.Account{
-			ID:      ID,
-			Number:  faker.Word(),
-			Balance: faker.RandomUnixTime(),
+		accReport := &report.Statement{
+			Number: faker.Word(),
 			Ledgers: []report.Ledger{
 				{
 					Action:  "deposit",
@@ -59,10 +59,10 @@ func TestConsolePrinter_PrintAccountStatement(t *testing.T) {
 		}
 
 		buf := &bytes.Buffer{}
-		accountReporter := &accountReporterMock{}
-		accountReporter.On("AccountDetailsFor", ID).Return(accReport, nil)
+		queries := &queryHandlerMock{}
+		queries.On("Handle", query.GetAccountStatement{ID: ID}).Return(accReport, nil)
 
-		printer := ui.NewConsolePrinter(buf, accountReporter)
+		printer := ui.NewConsolePrinter(buf, queries)
 
 		ledgers := bytes.Buffer{}
 		for idx, ledger := range accReport.Ledgers {
@@ -81,7 +81,7 @@ func TestConsolePrinter_PrintAccountStatement(t *testing.T) {
 
 		// assert
 		assert.NoError(t, err)
-		accountReporter.AssertExpectations(t)
+		queries.AssertExpectations(t)
 		assert.Equal(t, want, buf.String())
 	})
 
@@ -91,11 +91,11 @@ func TestConsolePrinter_PrintAccountStatement(t *testing.T) {
 
 		want := fmt.Errorf("some error occurred")
 
-		var account *report.Account
-		accountReporter := &accountReporterMock{}
-		accountReporter.On("AccountDetailsFor", ID).Return(account, want)
+		var statement *report.Statement
+		queries := &queryHandlerMock{}
+		queries.On("Handle", query.GetAccountStatement{ID: ID}).Return(statement, want)
 
-		printer := ui.NewConsolePrinter(&bytes.Buffer{}, accountReporter)
+		printer := ui.NewConsolePrinter(&bytes.Buffer{}, queries)
 
 		// act
 		err := printer.PrintAccountStatement(ID)
@@ -105,11 +105,11 @@ func TestConsolePrinter_PrintAccountStatement(t *testing.T) {
 	})
 }
 
-type accountReporterMock struct {
+type queryHandlerMock struct {
 	m.Mock
 }
 
-func (r *accountReporterMock) AccountDetailsFor(ID report.Identifier) (*report.Account, error) {
-	args := r.Called(ID)
-	return args.Get(0).(*report.Account), args.Error(1)
+func (h *queryHandlerMock) Handle(q cqrs.Query) (any, error) {
+	args := h.Called(q)
+	return args.Get(0), args.Error(1)
 }
@@ -0,0 +1,158 @@
+// Package naming provides the conventions which route commands and events to the methods handling them.
+//
+// The conventions are shared by the aggregates, see aggregate.WithNaming, and the event handlers,
+// see eventhandler.WithNaming.
+package naming
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/screwyprof/cqrs"
+)
+
+// ErrTypeCollision is returned when a command or an event is routed to a method which accepts another type.
+var ErrTypeCollision = errors.New("command or event type collision")
+
+var (
+	// Default is the naming convention used unless another one is configured.
+	//
+	// Command handlers are named exactly after cqrs.Command.CommandType(),
+	// event appliers and handlers are named "On" + cqrs.DomainEvent.EventType().
+	Default Naming = &MethodNaming{EventApplierPrefix: "On"} //nolint:gochecknoglobals
+
+	commandIntfType = reflect.TypeOf((*cqrs.Command)(nil)).Elem()     //nolint:gochecknoglobals
+	eventIntfType   = reflect.TypeOf((*cqrs.DomainEvent)(nil)).Elem() //nolint:gochecknoglobals
+)
+
+// Naming is a strategy which routes commands and events to the methods handling them.
+//
+// A method is registered under the key returned by CommandHandlerKey or EventApplierKey.
+// A command or an event is routed to the method registered under the key returned by CommandKey or EventKey.
+type Naming interface {
+	// CommandHandlerKey returns the routing key of a command handler method.
+	// It returns false if the method is not meant to be a command handler.
+	CommandHandlerKey(method reflect.Method) (string, bool)
+
+	// CommandKey returns the routing key of the given command.
+	CommandKey(c cqrs.Command) string
+
+	// EventApplierKey returns the routing key of an event applier or handler method.
+	// It returns false if the method is not meant to be an event applier.
+	EventApplierKey(method reflect.Method) (string, bool)
+
+	// EventKey returns the routing key of the given event.
+	EventKey(e cqrs.DomainEvent) string
+}
+
+// MethodNaming routes commands and events to the methods named after their types.
+//
+// A command is handled by the method named CommandHandlerPrefix + MethodName(c.CommandType()),
+// an event is applied by the method named EventApplierPrefix + MethodName(e.EventType()).
+// The registration metadata is cached per aggregate type for the last naming value it is used with,
+// so share a single MethodNaming, e.g. a package-level variable, rather than allocate one per aggregate.
+type MethodNaming struct {
+	CommandHandlerPrefix string
+	EventApplierPrefix   string
+
+	// MethodName converts a command or an event type to a method name, e.g. CamelCase.
+	// The type is used as is if MethodName is nil.
+	MethodName func(messageType string) string
+}
+
+// CommandHandlerKey implements Naming interface.
+func (n *MethodNaming) CommandHandlerKey(method reflect.Method) (string, bool) {
+	return method.Name, strings.HasPrefix(method.Name, n.CommandHandlerPrefix)
+}
+
+// CommandKey implements Naming interface.
+func (n *MethodNaming) CommandKey(c cqrs.Command) string {
+	return n.CommandHandlerPrefix + n.methodName(c.CommandType())
+}
+
+// EventApplierKey implements Naming interface.
+func (n *MethodNaming) EventApplierKey(method reflect.Method) (string, bool) {
+	return method.Name, strings.HasPrefix(method.Name, n.EventApplierPrefix)
+}
+
+// EventKey implements Naming interface.
+func (n *MethodNaming) EventKey(e cqrs.DomainEvent) string {
+	return n.EventApplierPrefix + n.methodName(e.EventType())
+}
+
+func (n *MethodNaming) methodName(messageType string) string {
+	if n.MethodName == nil {
+		return messageType
+	}
+
+	return n.MethodName(messageType)
+}
+
+// ParameterTypeNaming routes commands and events by the Go type of the method parameter.
+//
+// Neither the method names nor the CommandType and EventType strings are used for routing,
+// so renaming a method or a type string never breaks routing, and commands or events
+// which share a type string but live in different packages never collide.
+type ParameterTypeNaming struct{}
+
+// CommandHandlerKey implements Naming interface.
+func (ParameterTypeNaming) CommandHandlerKey(method reflect.Method) (string, bool) {
+	if method.Type.NumIn() != 2 || !method.Type.In(1).Implements(commandIntfType) {
+		return "", false
+	}
+
+	return TypeKey(method.Type.In(1)), true
+}
+
+// CommandKey implements Naming interface.
+func (ParameterTypeNaming) CommandKey(c cqrs.Command) string {
+	return TypeKey(reflect.TypeOf(c))
+}
+
+// EventApplierKey implements Naming interface.
+func (ParameterTypeNaming) EventApplierKey(method reflect.Method) (string, bool) {
+	if method.Type.NumIn() != 2 || !method.Type.In(1).Implements(eventIntfType) {
+		return "", false
+	}
+
+	return TypeKey(method.Type.In(1)), true
+}
+
+// EventKey implements Naming interface.
+func (ParameterTypeNaming) EventKey(e cqrs.DomainEvent) string {
+	return TypeKey(reflect.TypeOf(e))
+}
+
+// TypeKey returns a name of the given type which is unique across packages.
+func TypeKey(t reflect.Type) string {
+	if t.Kind() == reflect.Pointer {
+		return "*" + TypeKey(t.Elem())
+	}
+
+	if t.Name() == "" || t.PkgPath() == "" {
+		return t.String()
+	}
+
+	return t.PkgPath() + "." + t.Name()
+}
+
+// CamelCase converts a command or an event type which may contain dots, dashes or versions to a method name.
+//
+// For example "account.money_deposited.v2" becomes "AccountMoneyDepositedV2".
+func CamelCase(messageType string) string {
+	words := strings.FieldsFunc(messageType, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var b strings.Builder
+	for _, word := range words {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+
+		b.WriteString(string(runes))
+	}
+
+	return b.String()
+}
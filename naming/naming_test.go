@@ -0,0 +1,61 @@
+package naming_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/cqrs/aggregate/aggtest"
+	"github.com/screwyprof/cqrs/naming"
+)
+
+func TestCamelCase(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"SomethingHappened":          "SomethingHappened",
+		"something-happened.v2":      "SomethingHappenedV2",
+		"account.money_deposited.v2": "AccountMoneyDepositedV2",
+		"":                           "",
+	}
+
+	for messageType, want := range cases {
+		assert.Equal(t, want, naming.CamelCase(messageType), messageType)
+	}
+}
+
+func TestTypeKey(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it qualifies the type with its package", func(t *testing.T) {
+		t.Parallel()
+
+		got := naming.TypeKey(reflect.TypeOf(aggtest.SomethingHappened{}))
+
+		assert.Equal(t, "github.com/screwyprof/cqrs/aggregate/aggtest.SomethingHappened", got)
+	})
+
+	t.Run("it distinguishes a pointer from the type it points to", func(t *testing.T) {
+		t.Parallel()
+
+		got := naming.TypeKey(reflect.TypeOf(&aggtest.SomethingHappened{}))
+
+		assert.Equal(t, "*github.com/screwyprof/cqrs/aggregate/aggtest.SomethingHappened", got)
+	})
+}
+
+func TestParameterTypeNaming(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it routes events which share the event type by their Go type", func(t *testing.T) {
+		t.Parallel()
+
+		n := naming.ParameterTypeNaming{}
+
+		assert.NotEqual(t,
+			n.EventKey(aggtest.SomethingHappened{}),
+			n.EventKey(aggtest.SomethingHappenedElsewhere{}),
+		)
+	})
+}
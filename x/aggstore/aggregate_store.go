@@ -1,18 +1,63 @@
 package aggstore
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
 	"github.com/screwyprof/cqrs"
 	"github.com/screwyprof/cqrs/x"
+	"github.com/screwyprof/cqrs/x/logging"
 )
 
+// ErrMultiStreamNotSupported is returned when several aggregates are stored at once,
+// but the event store cannot append to several streams atomically.
+var ErrMultiStreamNotSupported = errors.New("event store does not support multi-stream appends")
+
+// ReplayError is returned when the loaded events cannot be replayed on the aggregate.
+//
+// It wraps the error returned by the aggregate, e.g. *aggregate.ApplyError
+// which tells the index and the type of the failing event.
+type ReplayError struct {
+	AggregateID   cqrs.Identifier
+	AggregateType string
+	Err           error
+}
+
+// Error implements error interface.
+func (e *ReplayError) Error() string {
+	return fmt.Sprintf("cannot replay %s %s: %v", e.AggregateType, e.AggregateID, e.Err)
+}
+
+// Unwrap returns the error returned by the aggregate.
+func (e *ReplayError) Unwrap() error {
+	return e.Err
+}
+
 // AggregateStore loads and stores aggregates.
 type AggregateStore struct {
 	aggregateFactory cqrs.AggregateFactory
 	eventStore       x.EventStore
+
+	logger *slog.Logger
+}
+
+// Option configures AggregateStore.
+type Option func(*AggregateStore)
+
+// WithLogger sets the logger the loaded and stored aggregates are logged with.
+//
+// The loaded and stored aggregates are logged at debug level, the aggregates which cannot be replayed at error level.
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *AggregateStore) {
+		s.logger = logger
+	}
 }
 
 // NewStore creates a new instance of AggregateStore.
-func NewStore(eventStore x.EventStore, aggregateFactory cqrs.AggregateFactory) *AggregateStore {
+func NewStore(eventStore x.EventStore, aggregateFactory cqrs.AggregateFactory, opts ...Option) *AggregateStore {
 	if eventStore == nil {
 		panic("eventStore is required")
 	}
@@ -21,14 +66,49 @@ func NewStore(eventStore x.EventStore, aggregateFactory cqrs.AggregateFactory) *
 		panic("aggregateFactory is required")
 	}
 
-	return &AggregateStore{
+	s := &AggregateStore{
 		eventStore:       eventStore,
 		aggregateFactory: aggregateFactory,
+		logger:           logging.Discard(),
+	}
+
+	for _, opt := range opts {
+		opt(s)
 	}
+
+	return s
 }
 
 // Load implements cqrs.AggregateStore interface.
+//
+// It returns a *ReplayError if the loaded events cannot be applied to the aggregate.
 func (s *AggregateStore) Load(aggregateID cqrs.Identifier, aggregateType string) (cqrs.ESAggregate, error) {
+	started := time.Now()
+
+	agg, err := s.load(aggregateID, aggregateType)
+
+	attrs := []slog.Attr{
+		logging.AggregateID(aggregateID),
+		logging.AggregateType(aggregateType),
+		logging.Duration(time.Since(started)),
+	}
+
+	var replayErr *ReplayError
+	if errors.As(err, &replayErr) {
+		s.logger.LogAttrs(context.Background(), slog.LevelError, "aggregate cannot be replayed",
+			append(attrs, logging.Err(err))...)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.LogAttrs(context.Background(), slog.LevelDebug, "aggregate loaded", append(attrs, logging.Version(agg.Version()))...)
+
+	return agg, nil
+}
+
+func (s *AggregateStore) load(aggregateID cqrs.Identifier, aggregateType string) (cqrs.ESAggregate, error) {
 	loadedEvents, err := s.eventStore.LoadEventsFor(aggregateID)
 	if err != nil {
 		return nil, err
@@ -41,13 +121,98 @@ func (s *AggregateStore) Load(aggregateID cqrs.Identifier, aggregateType string)
 
 	err = agg.Apply(loadedEvents...)
 	if err != nil {
-		return nil, err
+		return nil, &ReplayError{AggregateID: aggregateID, AggregateType: aggregateType, Err: err}
 	}
 
 	return agg, nil
 }
 
 // Store implements cqrs.AggregateStore interface.
+//
+// If the aggregate implements x.ChangeTracker, exactly its uncommitted changes are stored
+// and marked as committed, the given events are ignored.
+// The changes are marked as committed even if the events cannot be published, see x.PublishError.
 func (s *AggregateStore) Store(agg cqrs.ESAggregate, events ...cqrs.DomainEvent) error {
-	return s.eventStore.StoreEventsFor(agg.AggregateID(), agg.Version(), events)
+	version := agg.Version()
+
+	tracker, ok := agg.(x.ChangeTracker)
+	if ok {
+		version, events = tracker.OriginalVersion(), tracker.Changes()
+	}
+
+	err := s.eventStore.StoreEventsFor(agg.AggregateID(), version, events)
+	if !stored(err) {
+		return err
+	}
+
+	if ok {
+		tracker.MarkCommitted()
+	}
+
+	s.logStored(agg, version, events)
+
+	return err
+}
+
+// StoreMany implements x.MultiAggregateStore interface.
+//
+// The uncommitted changes of the aggregates which implement x.ChangeTracker are appended in a single
+// multi-stream append and marked as committed, even if the events cannot be published, see x.PublishError.
+// The aggregates which do not track their changes have nothing to store.
+// It returns ErrMultiStreamNotSupported unless the event store implements x.MultiStreamEventStore.
+func (s *AggregateStore) StoreMany(aggregates ...cqrs.ESAggregate) error {
+	eventStore, ok := s.eventStore.(x.MultiStreamEventStore)
+	if !ok {
+		return ErrMultiStreamNotSupported
+	}
+
+	appends := make([]x.StreamAppend, 0, len(aggregates))
+	changed := make([]cqrs.ESAggregate, 0, len(aggregates))
+
+	for _, agg := range aggregates {
+		tracker, ok := agg.(x.ChangeTracker)
+		if !ok || len(tracker.Changes()) == 0 {
+			continue
+		}
+
+		appends = append(appends, x.StreamAppend{
+			AggregateID: agg.AggregateID(),
+			Version:     tracker.OriginalVersion(),
+			Events:      tracker.Changes(),
+		})
+		changed = append(changed, agg)
+	}
+
+	if len(appends) == 0 {
+		return nil
+	}
+
+	err := eventStore.StoreEventsForMany(appends...)
+	if !stored(err) {
+		return err
+	}
+
+	for i, agg := range changed {
+		agg.(x.ChangeTracker).MarkCommitted() //nolint:forcetypeassert
+
+		s.logStored(agg, appends[i].Version, appends[i].Events)
+	}
+
+	return err
+}
+
+func (s *AggregateStore) logStored(agg cqrs.ESAggregate, version int, events []cqrs.DomainEvent) {
+	s.logger.LogAttrs(context.Background(), slog.LevelDebug, "aggregate stored",
+		logging.AggregateID(agg.AggregateID()),
+		logging.AggregateType(agg.AggregateType()),
+		logging.Version(version+len(events)),
+		logging.EventTypes(events),
+	)
+}
+
+// stored tells whether the events have been stored, even though they might not have been published.
+func stored(err error) bool {
+	var publishErr *x.PublishError
+
+	return err == nil || errors.As(err, &publishErr)
 }
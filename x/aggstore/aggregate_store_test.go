@@ -1,6 +1,9 @@
 package aggstore_test
 
 import (
+	"errors"
+	"fmt"
+	"log/slog"
 	"testing"
 
 	"github.com/go-faker/faker/v4"
@@ -11,12 +14,22 @@ import (
 	"github.com/screwyprof/cqrs/aggregate/aggtest"
 	"github.com/screwyprof/cqrs/x"
 	"github.com/screwyprof/cqrs/x/aggstore"
+	"github.com/screwyprof/cqrs/x/eventstore"
 	"github.com/screwyprof/cqrs/x/eventstore/evnstoretest"
+	"github.com/screwyprof/cqrs/x/logging/logtest"
 )
 
+var errCannotPublishEvents = errors.New("cannot publish events")
+
 // ensure that AggregateStore implements cqrs.AggregateStore interface.
 var _ x.AggregateStore = (*aggstore.AggregateStore)(nil)
 
+// ensure that AggregateStore implements x.MultiAggregateStore interface.
+var _ x.MultiAggregateStore = (*aggstore.AggregateStore)(nil)
+
+// ensure that EventSourced implements x.ChangeTracker interface.
+var _ x.ChangeTracker = (*aggregate.EventSourced)(nil)
+
 func TestNewStore(t *testing.T) {
 	t.Run("ItPanicsIfEventStoreIsNotGiven", func(t *testing.T) {
 		factory := func() {
@@ -75,6 +88,55 @@ func TestAggregateStoreLoad(t *testing.T) {
 
 		// assert
 		assert.ErrorIs(t, err, aggregate.ErrEventApplierNotFound)
+
+		var replayErr *aggstore.ReplayError
+		assert.ErrorAs(t, err, &replayErr)
+		assert.Equal(t, ID, replayErr.AggregateID)
+	})
+
+	t.Run("ItFailsIfAnEventApplierFails", func(t *testing.T) {
+		// arrange
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		s := createAggregateStore(
+			ID,
+			withLoadedEvents([]cqrs.DomainEvent{aggtest.SomethingHappened{}, aggtest.SomethingImpossibleHappened{}}),
+		)
+
+		// act
+		_, err := s.Load(ID, aggtest.TestAggregateType)
+
+		// assert
+		assert.ErrorIs(t, err, aggtest.ErrImpossibleTransition)
+
+		var applyErr *aggregate.ApplyError
+		assert.ErrorAs(t, err, &applyErr)
+		assert.Equal(t, 1, applyErr.Index)
+		assert.Equal(t, "SomethingImpossibleHappened", applyErr.EventType)
+	})
+
+	t.Run("ItLoadsADeciderAggregate", func(t *testing.T) {
+		// arrange
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+
+		aggFactory := aggregate.NewFactory()
+		aggFactory.RegisterAggregate(aggtest.TestAggregateType, func(ID cqrs.Identifier) cqrs.ESAggregate {
+			return aggregate.FromDecider(ID, aggtest.TestAggregateType, aggtest.NewTestDecider())
+		})
+
+		s := aggstore.NewStore(
+			createEventStoreMock([]cqrs.DomainEvent{aggtest.SomethingHappened{}}, nil, nil),
+			aggFactory,
+		)
+
+		// act
+		got, err := s.Load(ID, aggtest.TestAggregateType)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, 1, got.Version())
+
+		_, err = got.Handle(aggtest.MakeSomethingHappen{AggID: ID})
+		assert.ErrorIs(t, err, aggtest.ErrItCanHappenOnceOnly)
 	})
 
 	t.Run("ItReturnsAggregate", func(t *testing.T) {
@@ -106,6 +168,245 @@ func TestAggregateStoreStore(t *testing.T) {
 	})
 }
 
+func TestAggregateStoreStoreChanges(t *testing.T) {
+	t.Run("ItStoresTheUncommittedChanges", func(t *testing.T) {
+		// arrange
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+
+		var (
+			gotVersion int
+			gotEvents  []cqrs.DomainEvent
+		)
+
+		eventStore := &evnstoretest.EventStoreMock{
+			Saver: func(aggregateID cqrs.Identifier, version int, events []cqrs.DomainEvent) error {
+				gotVersion, gotEvents = version, events
+
+				return nil
+			},
+		}
+
+		agg := createAgg(ID)
+		_, err := agg.Handle(aggtest.MakeSomethingHappen{AggID: ID})
+		assert.NoError(t, err)
+
+		s := aggstore.NewStore(eventStore, aggregate.NewFactory())
+
+		// act
+		err = s.Store(agg)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, 0, gotVersion)
+		assert.Equal(t, []cqrs.DomainEvent{aggtest.SomethingHappened{}}, gotEvents)
+		assert.Empty(t, agg.Changes())
+		assert.Equal(t, 1, agg.OriginalVersion())
+	})
+
+	t.Run("ItKeepsTheChangesIfItCannotStoreThem", func(t *testing.T) {
+		// arrange
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		s := createAggregateStore(ID, withEventStoreSaveErr(evnstoretest.ErrEventStoreCannotStoreEvents))
+
+		agg := createAgg(ID)
+		_, err := agg.Handle(aggtest.MakeSomethingHappen{AggID: ID})
+		assert.NoError(t, err)
+
+		// act
+		err = s.Store(agg)
+
+		// assert
+		assert.ErrorIs(t, err, evnstoretest.ErrEventStoreCannotStoreEvents)
+		assert.Equal(t, []cqrs.DomainEvent{aggtest.SomethingHappened{}}, agg.Changes())
+		assert.Equal(t, 0, agg.OriginalVersion())
+	})
+}
+
+func TestAggregateStoreStoreCommitsPublishedChanges(t *testing.T) {
+	t.Run("ItMarksTheChangesAsCommittedIfTheEventsCannotBePublished", func(t *testing.T) {
+		// arrange
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		s := createAggregateStore(ID, withEventStoreSaveErr(&x.PublishError{Err: errCannotPublishEvents}))
+
+		agg := createAgg(ID)
+		_, err := agg.Handle(aggtest.MakeSomethingHappen{AggID: ID})
+		assert.NoError(t, err)
+
+		// act
+		err = s.Store(agg)
+
+		// assert
+		assert.ErrorIs(t, err, errCannotPublishEvents)
+		assert.Empty(t, agg.Changes())
+		assert.Equal(t, 1, agg.OriginalVersion())
+	})
+}
+
+func TestAggregateStoreStoreMany(t *testing.T) {
+	t.Run("ItStoresTheChangesOfAllTheAggregatesAtOnce", func(t *testing.T) {
+		// arrange
+		firstID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		secondID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+
+		es := eventstore.NewInMemoryEventStoreWithOutbox()
+		s := aggstore.NewStore(es, aggregate.NewFactory())
+
+		first, second := createAgg(firstID), createAgg(secondID)
+		_, err := first.Handle(aggtest.MakeSomethingHappen{AggID: firstID})
+		assert.NoError(t, err)
+
+		_, err = second.Handle(aggtest.MakeSomethingHappen{AggID: secondID})
+		assert.NoError(t, err)
+
+		// act
+		err = s.StoreMany(first, second)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Empty(t, first.Changes())
+		assert.Empty(t, second.Changes())
+
+		messages, err := es.MessagesAfter(0, 10)
+		assert.NoError(t, err)
+		assert.Len(t, messages, 2)
+	})
+
+	t.Run("ItStoresNothingIfAnyAggregateHasBeenModifiedConcurrently", func(t *testing.T) {
+		// arrange
+		firstID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		secondID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+
+		es := eventstore.NewInMemoryEventStoreWithOutbox()
+		s := aggstore.NewStore(es, aggregate.NewFactory())
+
+		first, second := createAgg(firstID), createAgg(secondID)
+		_, err := first.Handle(aggtest.MakeSomethingHappen{AggID: firstID})
+		assert.NoError(t, err)
+
+		_, err = second.Handle(aggtest.MakeSomethingHappen{AggID: secondID})
+		assert.NoError(t, err)
+
+		assert.NoError(t, es.StoreEventsFor(secondID, 0, []cqrs.DomainEvent{aggtest.SomethingElseHappened{}}))
+
+		// act
+		err = s.StoreMany(first, second)
+
+		// assert
+		assert.ErrorIs(t, err, eventstore.ErrConcurrencyViolation)
+		assert.Len(t, first.Changes(), 1)
+		assert.Len(t, second.Changes(), 1)
+	})
+
+	t.Run("ItFailsIfTheEventStoreCannotAppendToSeveralStreams", func(t *testing.T) {
+		// arrange
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		s := createAggregateStore(ID)
+
+		// act
+		err := s.StoreMany(createAgg(ID))
+
+		// assert
+		assert.ErrorIs(t, err, aggstore.ErrMultiStreamNotSupported)
+	})
+}
+
+func BenchmarkAggregateStoreLoad(b *testing.B) {
+	for _, n := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprintf("%d events", n), func(b *testing.B) {
+			ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+
+			loadedEvents := make([]cqrs.DomainEvent, n)
+			for i := range loadedEvents {
+				loadedEvents[i] = aggtest.SomethingHappened{}
+			}
+
+			aggFactory := aggregate.NewFactory()
+			aggFactory.RegisterAggregate(aggtest.TestAggregateType, func(ID cqrs.Identifier) cqrs.ESAggregate {
+				return aggregate.FromAggregate(aggtest.NewTestAggregate(ID))
+			})
+
+			s := aggstore.NewStore(createEventStoreMock(loadedEvents, nil, nil), aggFactory)
+
+			b.ReportAllocs()
+
+			for b.Loop() {
+				if _, err := s.Load(ID, aggtest.TestAggregateType); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestAggregateStoreLogging(t *testing.T) {
+	t.Run("ItLogsTheLoadedAggregate", func(t *testing.T) {
+		// arrange
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		logger, logs := logtest.NewLogger()
+		s := createAggregateStore(ID, withLoadedEvents([]cqrs.DomainEvent{aggtest.SomethingHappened{}}), withLogger(logger))
+
+		// act
+		_, err := s.Load(ID, aggtest.TestAggregateType)
+
+		// assert
+		assert.NoError(t, err)
+
+		entry, ok := logs.Find("aggregate loaded")
+		assert.True(t, ok)
+		assert.Equal(t, slog.LevelDebug, entry.Level)
+		assert.Equal(t, ID.String(), entry.Attrs["aggregate_id"])
+		assert.Equal(t, aggtest.TestAggregateType, entry.Attrs["aggregate_type"])
+		assert.Contains(t, entry.Attrs, "duration")
+	})
+
+	t.Run("ItLogsTheAggregateWhichCannotBeReplayed", func(t *testing.T) {
+		// arrange
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		logger, logs := logtest.NewLogger()
+		s := createAggregateStore(
+			ID,
+			withLoadedEvents([]cqrs.DomainEvent{aggtest.SomethingImpossibleHappened{}}),
+			withLogger(logger),
+		)
+
+		// act
+		_, err := s.Load(ID, aggtest.TestAggregateType)
+
+		// assert
+		assert.Error(t, err)
+
+		entry, ok := logs.Find("aggregate cannot be replayed")
+		assert.True(t, ok)
+		assert.Equal(t, slog.LevelError, entry.Level)
+		assert.Equal(t, err, entry.Attrs["error"])
+	})
+
+	t.Run("ItLogsTheStoredAggregate", func(t *testing.T) {
+		// arrange
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		logger, logs := logtest.NewLogger()
+		s := createAggregateStore(ID, withLogger(logger))
+
+		agg, err := s.Load(ID, aggtest.TestAggregateType)
+		assert.NoError(t, err)
+
+		_, err = agg.Handle(aggtest.MakeSomethingHappen{AggID: ID})
+		assert.NoError(t, err)
+
+		// act
+		err = s.Store(agg)
+
+		// assert
+		assert.NoError(t, err)
+
+		entry, ok := logs.Find("aggregate stored")
+		assert.True(t, ok)
+		assert.Equal(t, slog.LevelDebug, entry.Level)
+		assert.Equal(t, int64(1), entry.Attrs["version"])
+		assert.Equal(t, []string{"SomethingHappened"}, entry.Attrs["event_types"])
+	})
+}
+
 func createAgg(id cqrs.Identifier) *aggregate.EventSourced {
 	agg := aggtest.NewTestAggregate(id)
 
@@ -125,6 +426,8 @@ type aggregateStoreOptions struct {
 
 	loadErr  error
 	storeErr error
+
+	logger *slog.Logger
 }
 
 type option func(*aggregateStoreOptions)
@@ -159,6 +462,12 @@ func withEventStoreSaveErr(err error) option {
 	}
 }
 
+func withLogger(logger *slog.Logger) option {
+	return func(o *aggregateStoreOptions) {
+		o.logger = logger
+	}
+}
+
 func createAggregateStore(id cqrs.Identifier, opts ...option) *aggstore.AggregateStore {
 	config := &aggregateStoreOptions{}
 	for _, opt := range opts {
@@ -183,7 +492,11 @@ func createAggregateStore(id cqrs.Identifier, opts ...option) *aggstore.Aggregat
 	aggFactory := createAggFactory(esAgg, config.emptyFactory)
 	eventStore := createEventStoreMock(config.loadedEvents, config.loadErr, config.storeErr)
 
-	return aggstore.NewStore(eventStore, aggFactory)
+	if config.logger == nil {
+		return aggstore.NewStore(eventStore, aggFactory)
+	}
+
+	return aggstore.NewStore(eventStore, aggFactory, aggstore.WithLogger(config.logger))
 }
 
 func createAggFactory(agg *aggregate.EventSourced, empty bool) *aggregate.Factory {
@@ -17,6 +17,8 @@ var (
 type AggregateStoreMock struct {
 	Loader func(aggregateID cqrs.Identifier, aggregateType string) (cqrs.ESAggregate, error)
 	Saver  func(aggregate cqrs.ESAggregate, events ...cqrs.DomainEvent) error
+
+	ManySaver func(aggregates ...cqrs.ESAggregate) error
 }
 
 // Load implements cqrs.AggregateStore interface.
@@ -30,3 +32,8 @@ func (m *AggregateStoreMock) Load(
 func (m *AggregateStoreMock) Store(aggregate cqrs.ESAggregate, events ...cqrs.DomainEvent) error {
 	return m.Saver(aggregate, events...)
 }
+
+// StoreMany implements x.MultiAggregateStore interface.
+func (m *AggregateStoreMock) StoreMany(aggregates ...cqrs.ESAggregate) error {
+	return m.ManySaver(aggregates...)
+}
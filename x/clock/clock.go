@@ -0,0 +1,73 @@
+// Package clock provides the x.Clock implementations: the system clock and the fakes for testing.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// System tells the system time.
+type System struct{}
+
+// NewSystem creates a new instance of System.
+func NewSystem() System {
+	return System{}
+}
+
+// Now implements x.Clock interface.
+func (System) Now() time.Time {
+	return time.Now()
+}
+
+// Fixed always tells the same time.
+type Fixed struct {
+	now time.Time
+}
+
+// NewFixed creates a new instance of Fixed which tells the given time.
+func NewFixed(now time.Time) Fixed {
+	return Fixed{now: now}
+}
+
+// Now implements x.Clock interface.
+func (c Fixed) Now() time.Time {
+	return c.now
+}
+
+// Step is a fake clock which moves only by the given step each time it is asked for the time,
+// or when it is advanced explicitly.
+//
+// It is safe for concurrent use.
+type Step struct {
+	now  time.Time
+	step time.Duration
+	mu   sync.Mutex
+}
+
+// NewStep creates a new instance of Step which starts at the given time.
+//
+// A zero step makes the clock stand still until it is advanced.
+func NewStep(start time.Time, step time.Duration) *Step {
+	return &Step{now: start, step: step}
+}
+
+// Now implements x.Clock interface.
+//
+// It returns the current time and moves the clock by the step.
+func (c *Step) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now
+	c.now = c.now.Add(c.step)
+
+	return now
+}
+
+// Advance moves the clock by the given duration.
+func (c *Step) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
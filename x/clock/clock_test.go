@@ -0,0 +1,109 @@
+package clock_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/cqrs/x"
+	"github.com/screwyprof/cqrs/x/clock"
+)
+
+// ensure that the clocks implement x.Clock interface.
+var (
+	_ x.Clock = clock.System{}
+	_ x.Clock = clock.Fixed{}
+	_ x.Clock = (*clock.Step)(nil)
+)
+
+func TestSystem(t *testing.T) {
+	t.Run("ItTellsTheSystemTime", func(t *testing.T) {
+		// arrange
+		before := time.Now()
+
+		// act
+		got := clock.NewSystem().Now()
+
+		// assert
+		assert.False(t, got.Before(before))
+		assert.False(t, got.After(time.Now()))
+	})
+}
+
+func TestFixed(t *testing.T) {
+	t.Run("ItAlwaysTellsTheSameTime", func(t *testing.T) {
+		// arrange
+		now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+		c := clock.NewFixed(now)
+
+		// act
+		first, second := c.Now(), c.Now()
+
+		// assert
+		assert.Equal(t, now, first)
+		assert.Equal(t, now, second)
+	})
+}
+
+func TestStep(t *testing.T) {
+	start := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("ItMovesByTheStepEachTimeItIsAsked", func(t *testing.T) {
+		// arrange
+		c := clock.NewStep(start, time.Second)
+
+		// act
+		first, second := c.Now(), c.Now()
+
+		// assert
+		assert.Equal(t, start, first)
+		assert.Equal(t, start.Add(time.Second), second)
+	})
+
+	t.Run("ItStandsStillWithoutAStepUntilAdvanced", func(t *testing.T) {
+		// arrange
+		c := clock.NewStep(start, 0)
+
+		// act
+		before := c.Now()
+		c.Advance(time.Hour)
+		after := c.Now()
+
+		// assert
+		assert.Equal(t, start, before)
+		assert.Equal(t, start.Add(time.Hour), after)
+	})
+
+	t.Run("ItTellsDistinctTimesToConcurrentCallers", func(t *testing.T) {
+		// arrange
+		const callers = 10
+
+		c := clock.NewStep(start, time.Millisecond)
+		times := make(chan time.Time, callers)
+
+		// act
+		var wg sync.WaitGroup
+		for range callers {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				times <- c.Now()
+			}()
+		}
+
+		wg.Wait()
+		close(times)
+
+		// assert
+		seen := make(map[time.Time]struct{})
+		for tm := range times {
+			seen[tm] = struct{}{}
+		}
+
+		assert.Len(t, seen, callers)
+	})
+}
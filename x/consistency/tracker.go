@@ -0,0 +1,80 @@
+// Package consistency lets queries wait until an asynchronously updated read model catches up with a command.
+package consistency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/screwyprof/cqrs/x"
+)
+
+// ErrNotConsistent is returned when the read model has not caught up with the token in time.
+var ErrNotConsistent = errors.New("read model has not caught up")
+
+// Tracker keeps track of the global position a read model has processed the events up to.
+//
+// It implements x.Checkpoint interface, so passing it to outbox.WithCheckpoint makes the relay
+// advance the position once the events have been delivered to the read model.
+// It is safe for concurrent use.
+type Tracker struct {
+	position int
+	advanced chan struct{}
+	mu       sync.RWMutex
+}
+
+// NewTracker creates a new instance of Tracker.
+func NewTracker() *Tracker {
+	return &Tracker{
+		advanced: make(chan struct{}),
+	}
+}
+
+// Position implements x.Checkpoint interface.
+func (t *Tracker) Position() (int, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.position, nil
+}
+
+// Save implements x.Checkpoint interface.
+//
+// It wakes up the queries waiting for the read model to catch up.
+func (t *Tracker) Save(position int) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if position <= t.position {
+		return nil
+	}
+
+	t.position = position
+
+	close(t.advanced)
+	t.advanced = make(chan struct{})
+
+	return nil
+}
+
+// WaitFor blocks until the read model has processed the events up to the position of the token.
+//
+// Use context.WithTimeout to bound the wait, ErrNotConsistent is returned once the context is done.
+func (t *Tracker) WaitFor(ctx context.Context, token x.ConsistencyToken) error {
+	for {
+		t.mu.RLock()
+		position, advanced := t.position, t.advanced
+		t.mu.RUnlock()
+
+		if position >= token.Position {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: at position %d, waiting for %d: %w", ErrNotConsistent, position, token.Position, ctx.Err())
+		case <-advanced:
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package consistency_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-faker/faker/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/cqrs"
+	"github.com/screwyprof/cqrs/aggregate"
+	"github.com/screwyprof/cqrs/aggregate/aggtest"
+	"github.com/screwyprof/cqrs/x"
+	"github.com/screwyprof/cqrs/x/aggstore"
+	"github.com/screwyprof/cqrs/x/consistency"
+	"github.com/screwyprof/cqrs/x/dispatcher"
+	"github.com/screwyprof/cqrs/x/eventbus"
+	"github.com/screwyprof/cqrs/x/eventhandler/evnhndtest"
+	"github.com/screwyprof/cqrs/x/eventstore"
+	"github.com/screwyprof/cqrs/x/outbox"
+)
+
+// ensure that Tracker implements x.Checkpoint interface.
+var _ x.Checkpoint = (*consistency.Tracker)(nil)
+
+func TestTrackerWaitFor(t *testing.T) {
+	t.Run("ItReturnsAtOnceIfTheReadModelHasCaughtUp", func(t *testing.T) {
+		// arrange
+		tracker := consistency.NewTracker()
+		assert.NoError(t, tracker.Save(2))
+
+		// act
+		err := tracker.WaitFor(context.Background(), x.ConsistencyToken{Position: 2})
+
+		// assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("ItWaitsUntilTheReadModelCatchesUp", func(t *testing.T) {
+		// arrange
+		tracker := consistency.NewTracker()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		go func() {
+			for position := 1; position <= 3; position++ {
+				_ = tracker.Save(position)
+			}
+		}()
+
+		// act
+		err := tracker.WaitFor(ctx, x.ConsistencyToken{Position: 3})
+
+		// assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("ItFailsIfTheReadModelDoesNotCatchUpInTime", func(t *testing.T) {
+		// arrange
+		tracker := consistency.NewTracker()
+		assert.NoError(t, tracker.Save(1))
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		defer cancel()
+
+		// act
+		err := tracker.WaitFor(ctx, x.ConsistencyToken{Position: 2})
+
+		// assert
+		assert.ErrorIs(t, err, consistency.ErrNotConsistent)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestTrackerSave(t *testing.T) {
+	t.Run("ItNeverMovesThePositionBackwards", func(t *testing.T) {
+		// arrange
+		tracker := consistency.NewTracker()
+		assert.NoError(t, tracker.Save(5))
+
+		// act
+		err := tracker.Save(3)
+
+		// assert
+		assert.NoError(t, err)
+
+		position, _ := tracker.Position()
+		assert.Equal(t, 5, position)
+	})
+}
+
+func TestReadYourWrites(t *testing.T) {
+	t.Run("ItWaitsUntilTheRelayDeliversTheEventsOfTheCommand", func(t *testing.T) {
+		// arrange
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		es := eventstore.NewInMemoryEventStoreWithOutbox()
+
+		aggFactory := aggregate.NewFactory()
+		aggFactory.RegisterAggregate(aggtest.TestAggregateType, func(ID cqrs.Identifier) cqrs.ESAggregate {
+			return aggregate.FromAggregate(aggtest.NewTestAggregate(ID))
+		})
+
+		d := dispatcher.NewDispatcher(aggstore.NewStore(es, aggFactory), dispatcher.WithPositionReader(es))
+
+		readModel := &evnhndtest.EventHandlerMock{}
+		eventBus := eventbus.NewInMemoryEventBus()
+		eventBus.Register(readModel)
+
+		tracker := consistency.NewTracker()
+		relay := outbox.NewRelay(es, eventBus, outbox.WithCheckpoint(tracker))
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		// act
+		_, token, err := d.HandleWithToken(aggtest.MakeSomethingHappen{AggID: ID})
+		assert.NoError(t, err)
+
+		go func() {
+			_, _ = relay.Deliver(ctx)
+		}()
+
+		err = tracker.WaitFor(ctx, token)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, []cqrs.DomainEvent{aggtest.SomethingHappened{}}, readModel.Happened)
+	})
+}
@@ -1,6 +1,15 @@
 package x
 
-import "github.com/screwyprof/cqrs"
+import (
+	"errors"
+	"time"
+
+	"github.com/screwyprof/cqrs"
+)
+
+// ErrConcurrencyViolation is returned by an event store when the stream has been modified
+// since the aggregate was loaded.
+var ErrConcurrencyViolation = errors.New("concurrency error: aggregate versions differ")
 
 // EventStore stores and loads events.
 type EventStore interface {
@@ -8,6 +17,103 @@ type EventStore interface {
 	StoreEventsFor(aggregateID cqrs.Identifier, version int, events []cqrs.DomainEvent) error
 }
 
+// StreamAppend holds the events to append to the stream of the given aggregate
+// which is expected to be at the given version.
+type StreamAppend struct {
+	AggregateID cqrs.Identifier
+	Version     int
+	Events      []cqrs.DomainEvent
+}
+
+// MultiStreamEventStore is an event store which can append events to several streams atomically.
+//
+// Either all the appends succeed, or none of them is applied.
+// The events are published only after all of them have been stored.
+type MultiStreamEventStore interface {
+	EventStore
+	StoreEventsForMany(appends ...StreamAppend) error
+}
+
+// PublishError is returned by an event store when the events have been stored but cannot be published.
+//
+// The changes which produced the events are stored, so they must not be stored again.
+type PublishError struct {
+	Err error
+}
+
+// Error implements error interface.
+func (e *PublishError) Error() string {
+	return "events are stored but cannot be published: " + e.Err.Error()
+}
+
+// Unwrap returns the error returned by the publisher.
+func (e *PublishError) Unwrap() error {
+	return e.Err
+}
+
+// PositionReader reports the global position of the last stored event.
+//
+// Positions start at 1 and grow monotonically across all the streams in the order the events have been stored.
+type PositionReader interface {
+	LastPosition() (int, error)
+}
+
+// ConsistencyToken identifies the state of the write side right after a command has been handled.
+//
+// A read model which has processed the events up to Position reflects the changes made by the command.
+type ConsistencyToken struct {
+	// AggregateID is the identifier of the aggregate which handled the command.
+	AggregateID cqrs.Identifier
+
+	// Version is the revision of the aggregate stream after the command.
+	Version int
+
+	// Position is the global position the events produced by the command are at or before.
+	Position int
+}
+
+// OutboxMessage is an event recorded for publication at the given position of the outbox.
+//
+// Positions start at 1 and grow monotonically in the order the events have been stored.
+type OutboxMessage struct {
+	Position int
+	Event    cqrs.DomainEvent
+}
+
+// Outbox holds the events recorded for publication atomically with appending them to the streams.
+type Outbox interface {
+	// MessagesAfter returns at most limit messages recorded after the given position.
+	MessagesAfter(position, limit int) ([]OutboxMessage, error)
+}
+
+// OutboxPruner is implemented by the outboxes which can drop the delivered messages.
+type OutboxPruner interface {
+	// Prune drops the messages recorded up to and including the given position.
+	Prune(position int) error
+}
+
+// Checkpoint keeps track of the position of the last delivered outbox message.
+type Checkpoint interface {
+	Position() (int, error)
+	Save(position int) error
+}
+
+// InboxStore records the events processed by the event handlers.
+type InboxStore interface {
+	// Processed reports whether the given handler has already processed the event.
+	Processed(handlerID, eventID string) (bool, error)
+
+	// MarkProcessed records that the given handler has processed the event.
+	MarkProcessed(handlerID, eventID string) error
+}
+
+// Transactor runs a function in a transaction.
+//
+// The transaction is committed if the function succeeds and rolled back otherwise.
+type Transactor interface {
+	InTransaction(fn func() error) error
+}
+
 // EventPublisher publishes events.
 type EventPublisher interface {
 	Publish(e ...cqrs.DomainEvent) error
@@ -22,8 +128,58 @@ type EventHandler interface {
 // EventHandlerFunc is a function that can be used as an event handler.
 type EventHandlerFunc func(cqrs.DomainEvent) error
 
+// Clock tells the current time.
+//
+// Inject it into the aggregates through their factory functions instead of calling time.Now,
+// so that the produced events are predictable in tests.
+type Clock interface {
+	Now() time.Time
+}
+
+// IDGenerator generates unique identifiers.
+type IDGenerator interface {
+	NewID() string
+}
+
+// EventMetadata describes an occurrence of an event.
+//
+// Embed it into an event to have the dispatcher fill it in when the event is produced.
+type EventMetadata struct {
+	EventID    string
+	OccurredAt time.Time
+
+	// TraceParent is the W3C traceparent of the span which stored the event within the trace of the command
+	// which produced it, it correlates the handling of the event with the command when the dispatcher is traced.
+	TraceParent string
+}
+
+// Metadata implements MetadataCarrier interface.
+func (m EventMetadata) Metadata() EventMetadata {
+	return m
+}
+
+// MetadataCarrier is implemented by the events which embed EventMetadata.
+type MetadataCarrier interface {
+	Metadata() EventMetadata
+}
+
+// ChangeTracker is implemented by aggregates which keep track of their uncommitted changes.
+type ChangeTracker interface {
+	Changes() []cqrs.DomainEvent
+	OriginalVersion() int
+	MarkCommitted()
+}
+
 // AggregateStore loads and stores the aggregate.
 type AggregateStore interface {
 	Load(aggregateID cqrs.Identifier, aggregateType string) (cqrs.ESAggregate, error)
 	Store(aggregate cqrs.ESAggregate, events ...cqrs.DomainEvent) error
 }
+
+// MultiAggregateStore is an aggregate store which can store the changes of several aggregates atomically.
+//
+// Either the changes of all the aggregates are stored, or none of them is.
+type MultiAggregateStore interface {
+	AggregateStore
+	StoreMany(aggregates ...cqrs.ESAggregate) error
+}
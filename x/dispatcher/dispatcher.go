@@ -1,10 +1,20 @@
 package dispatcher
 
 import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
 	"github.com/screwyprof/cqrs"
 	"github.com/screwyprof/cqrs/x"
+	"github.com/screwyprof/cqrs/x/logging"
+	"github.com/screwyprof/cqrs/x/tracing"
 )
 
+// ErrPositionsNotTracked is returned when a consistency token is requested but no position reader is configured.
+var ErrPositionsNotTracked = errors.New("global positions are not tracked")
+
 // Dispatcher is a basic message dispatcher.
 //
 // It drives the overall command handling and event application/distribution process.
@@ -12,35 +22,178 @@ import (
 // at startup and keep it in memory.
 // Depends on some kind of event storage mechanism.
 type Dispatcher struct {
-	store x.AggregateStore
+	store     x.AggregateStore
+	positions x.PositionReader
+
+	clock x.Clock
+	ids   x.IDGenerator
+
+	logger *slog.Logger
+	tracer tracing.Tracer
+}
+
+// Option configures Dispatcher.
+type Option func(*Dispatcher)
+
+// WithPositionReader sets the source of the global positions the consistency tokens are issued with,
+// usually it is the event store the aggregate store writes to.
+func WithPositionReader(positions x.PositionReader) Option {
+	return func(d *Dispatcher) {
+		d.positions = positions
+	}
+}
+
+// WithClock sets the clock the occurrence time of the produced events is taken from.
+//
+// See WithIDGenerator for the events it applies to.
+func WithClock(clock x.Clock) Option {
+	return func(d *Dispatcher) {
+		d.clock = clock
+	}
+}
+
+// WithIDGenerator sets the generator of the produced event identifiers.
+//
+// The produced events which embed x.EventMetadata get it filled in before they are stored,
+// the fields the aggregate has already set are kept as is.
+func WithIDGenerator(ids x.IDGenerator) Option {
+	return func(d *Dispatcher) {
+		d.ids = ids
+	}
+}
+
+// WithLogger sets the logger the handled commands are logged with.
+//
+// The handled commands are logged at debug level, the failed ones at error level.
+func WithLogger(logger *slog.Logger) Option {
+	return func(d *Dispatcher) {
+		d.logger = logger
+	}
+}
+
+// WithTracer sets the tracer the handled commands are traced with.
+//
+// A command is traced with a span which has a child span for loading and replaying the aggregate,
+// handling the command and storing the produced events. The trace parent of the storing span
+// is recorded in the produced events which embed x.EventMetadata, so that the spans of appending
+// and delivering them continue the trace as its children.
+func WithTracer(tracer tracing.Tracer) Option {
+	return func(d *Dispatcher) {
+		d.tracer = tracer
+	}
 }
 
 // NewDispatcher creates a new instance of Dispatcher.
-func NewDispatcher(aggregateStore x.AggregateStore) *Dispatcher {
+func NewDispatcher(aggregateStore x.AggregateStore, opts ...Option) *Dispatcher {
 	if aggregateStore == nil {
 		panic("aggregateStore is required")
 	}
 
-	return &Dispatcher{
-		store: aggregateStore,
+	d := &Dispatcher{
+		store:  aggregateStore,
+		logger: logging.Discard(),
+		tracer: tracing.Noop(),
 	}
+
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	return d
 }
 
 // Handle implements cqrs.CommandHandler interface.
 func (d *Dispatcher) Handle(c cqrs.Command) ([]cqrs.DomainEvent, error) {
+	events, _, err := d.handle(c)
+
+	return events, err
+}
+
+// HandleWithToken handles the command and returns a consistency token along with the produced events.
+//
+// The token lets a query wait until an asynchronously updated read model catches up with the command.
+// The position is read right after the events have been stored, so it may be ahead of them
+// if other commands are handled concurrently, which is safe to wait for.
+// It returns ErrPositionsNotTracked unless the dispatcher has been created WithPositionReader.
+func (d *Dispatcher) HandleWithToken(c cqrs.Command) ([]cqrs.DomainEvent, x.ConsistencyToken, error) {
+	if d.positions == nil {
+		return nil, x.ConsistencyToken{}, ErrPositionsNotTracked
+	}
+
+	events, version, err := d.handle(c)
+	if err != nil {
+		return nil, x.ConsistencyToken{}, err
+	}
+
+	position, err := d.positions.LastPosition()
+	if err != nil {
+		return nil, x.ConsistencyToken{}, err
+	}
+
+	return events, x.ConsistencyToken{AggregateID: c.AggregateID(), Version: version, Position: position}, nil
+}
+
+func (d *Dispatcher) handle(c cqrs.Command) ([]cqrs.DomainEvent, int, error) {
+	started := time.Now()
+
+	attrs := []slog.Attr{
+		logging.CommandType(c),
+		logging.AggregateID(c.AggregateID()),
+		logging.AggregateType(c.AggregateType()),
+	}
+
+	ctx, span := d.tracer.Start(context.Background(), "cqrs.dispatch", attrs...)
+
+	events, version, err := d.handleCommand(ctx, c)
+
+	tracing.End(span, err)
+
+	attrs = append(attrs, logging.Duration(time.Since(started)))
+
+	if err != nil {
+		d.logger.LogAttrs(ctx, slog.LevelError, "command failed", append(attrs, logging.Err(err))...)
+
+		return nil, 0, err
+	}
+
+	d.logger.LogAttrs(ctx, slog.LevelDebug, "command handled",
+		append(attrs, logging.Version(version), logging.EventTypes(events))...)
+
+	return events, version, nil
+}
+
+func (d *Dispatcher) handleCommand(ctx context.Context, c cqrs.Command) ([]cqrs.DomainEvent, int, error) {
+	_, span := d.tracer.Start(ctx, "cqrs.aggregate.load")
 	agg, err := d.store.Load(c.AggregateID(), c.AggregateType())
+	if err == nil {
+		span.SetAttributes(slog.Int("replayed_events", agg.Version()))
+	}
+	tracing.End(span, err)
+
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
+	version := agg.Version()
+
+	_, span = d.tracer.Start(ctx, "cqrs.aggregate.handle")
 	events, err := agg.Handle(c)
+	tracing.End(span, err)
+
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	if err = d.store.Store(agg, events...); err != nil {
-		return nil, err
+	// the events carry the trace parent of the store span, so that the event store and the event handlers
+	// continue the trace under the span which stores the events.
+	_, span = d.tracer.Start(ctx, "cqrs.aggregate.store", logging.EventTypes(events))
+	agg, events = d.stampMetadata(agg, events, span.TraceParent())
+	err = d.store.Store(agg, events...)
+	tracing.End(span, err)
+
+	if err != nil {
+		return nil, 0, err
 	}
 
-	return events, nil
+	return events, version + len(events), nil
 }
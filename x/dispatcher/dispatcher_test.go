@@ -1,6 +1,8 @@
 package dispatcher_test
 
 import (
+	"errors"
+	"log/slog"
 	"testing"
 
 	"github.com/go-faker/faker/v4"
@@ -9,11 +11,16 @@ import (
 	"github.com/screwyprof/cqrs"
 	"github.com/screwyprof/cqrs/aggregate"
 	"github.com/screwyprof/cqrs/aggregate/aggtest"
+	"github.com/screwyprof/cqrs/aggregate/aggtest/specdoc"
+	"github.com/screwyprof/cqrs/x"
 	"github.com/screwyprof/cqrs/x/aggstore/aggstoretest"
 	"github.com/screwyprof/cqrs/x/dispatcher"
 	. "github.com/screwyprof/cqrs/x/dispatcher/testdsl"
+	"github.com/screwyprof/cqrs/x/logging/logtest"
 )
 
+var errCannotReadPosition = errors.New("cannot read position")
+
 // ensure that Dispatcher  implements cqrs.CommandHandler interface.
 var _ cqrs.CommandHandler = (*dispatcher.Dispatcher)(nil)
 
@@ -73,11 +80,223 @@ func TestNewDispatcherHandle(t *testing.T) {
 	})
 }
 
+func TestDispatcherPipeline(t *testing.T) {
+	t.Run("ItHandlesTheCommandAgainstThePriorEvents", func(t *testing.T) {
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		TestPipeline(t)(
+			NewPipeline(createAggregateFactory()).WithEvents(ID, aggtest.SomethingHappened{}),
+			When(aggtest.MakeSomethingHappen{AggID: ID}),
+			ThenFailWith(aggtest.ErrItCanHappenOnceOnly),
+		)
+	})
+
+	t.Run("ItKeepsThePriorEventsOfEachAggregateApart", func(t *testing.T) {
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		otherID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		TestPipeline(t)(
+			NewPipeline(createAggregateFactory()).WithEvents(otherID, aggtest.SomethingHappened{}),
+			When(aggtest.MakeSomethingHappen{AggID: ID}),
+			Then(aggtest.SomethingHappened{}),
+		)
+	})
+
+	t.Run("ItReturnsAndPublishesTheEventsOfAllTheCommands", func(t *testing.T) {
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		otherID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		TestPipeline(t)(
+			NewPipeline(createAggregateFactory()),
+			When(aggtest.MakeSomethingHappen{AggID: ID}, aggtest.MakeSomethingHappen{AggID: otherID}),
+			Then(aggtest.SomethingHappened{}, aggtest.SomethingHappened{}),
+		)
+	})
+
+	t.Run("ItPublishesTheEventsOfTheCommandsHandledBeforeTheFailingOne", func(t *testing.T) {
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		TestPipeline(t)(
+			NewPipeline(createAggregateFactory()),
+			When(aggtest.MakeSomethingHappen{AggID: ID}, aggtest.MakeSomethingHappen{AggID: ID}),
+			ThenFailWith(aggtest.ErrItCanHappenOnceOnly),
+		)
+	})
+
+	t.Run("ItFailsIfThePriorEventsCannotBeApplied", func(t *testing.T) {
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		TestPipeline(t)(
+			NewPipeline(createAggregateFactory()).WithEvents(ID, aggtest.SomethingImpossibleHappened{}),
+			When(aggtest.MakeSomethingHappen{AggID: ID}),
+			ThenFailWith(aggtest.ErrImpossibleTransition),
+		)
+	})
+
+	t.Run("ItStoresThePriorEventsOnce", func(t *testing.T) {
+		// arrange
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		pipeline := NewPipeline(createAggregateFactory()).WithEvents(ID, aggtest.SomethingHappened{})
+
+		// act
+		_, firstErr := pipeline.Handle(aggtest.MakeSomethingHappen{AggID: aggtest.StringIdentifier(faker.UUIDHyphenated())})
+		_, secondErr := pipeline.Handle(aggtest.MakeSomethingHappen{AggID: aggtest.StringIdentifier(faker.UUIDHyphenated())})
+
+		// assert
+		assert.NoError(t, firstErr)
+		assert.NoError(t, secondErr)
+	})
+
+	t.Run("ItRecordsTheScenarioIntoTheReporter", func(t *testing.T) {
+		// arrange
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		reporter := specdoc.NewReporter()
+
+		// act
+		TestPipeline(t, WithReporter(reporter))(
+			NewPipeline(createAggregateFactory()).WithEvents(ID, aggtest.SomethingHappened{}),
+			When(aggtest.MakeSomethingHappen{AggID: ID}),
+			ThenFailWith(aggtest.ErrItCanHappenOnceOnly),
+		)
+
+		// assert
+		want := []specdoc.Feature{{
+			Aggregate: aggtest.TestAggregateType,
+			Scenarios: []specdoc.Scenario{{
+				Aggregate: aggtest.TestAggregateType,
+				Name:      "ItRecordsTheScenarioIntoTheReporter",
+				Given:     []cqrs.DomainEvent{aggtest.SomethingHappened{}},
+				When:      []cqrs.Command{aggtest.MakeSomethingHappen{AggID: ID}},
+				Err:       aggtest.ErrItCanHappenOnceOnly,
+			}},
+		}}
+		assert.Equal(t, want, reporter.Features())
+	})
+}
+
+func TestDispatcherLogging(t *testing.T) {
+	t.Run("ItLogsTheHandledCommand", func(t *testing.T) {
+		// arrange
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		logger, logs := logtest.NewLogger()
+		d := createDispatcher(ID, withLogger(logger))
+
+		// act
+		_, err := d.Handle(aggtest.MakeSomethingHappen{AggID: ID})
+
+		// assert
+		assert.NoError(t, err)
+
+		entry, ok := logs.Find("command handled")
+		assert.True(t, ok)
+		assert.Equal(t, slog.LevelDebug, entry.Level)
+		assert.Equal(t, "MakeSomethingHappen", entry.Attrs["command_type"])
+		assert.Equal(t, ID.String(), entry.Attrs["aggregate_id"])
+		assert.Equal(t, aggtest.TestAggregateType, entry.Attrs["aggregate_type"])
+		assert.Equal(t, int64(1), entry.Attrs["version"])
+		assert.Equal(t, []string{"SomethingHappened"}, entry.Attrs["event_types"])
+		assert.Contains(t, entry.Attrs, "duration")
+	})
+
+	t.Run("ItLogsTheFailedCommand", func(t *testing.T) {
+		// arrange
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		logger, logs := logtest.NewLogger()
+		d := createDispatcher(ID, withLogger(logger), withAggregateStoreSaveErr(aggstoretest.ErrAggregateStoreCannotStoreAggregate))
+
+		// act
+		_, err := d.Handle(aggtest.MakeSomethingHappen{AggID: ID})
+
+		// assert
+		assert.Error(t, err)
+
+		entry, ok := logs.Find("command failed")
+		assert.True(t, ok)
+		assert.Equal(t, slog.LevelError, entry.Level)
+		assert.Equal(t, "MakeSomethingHappen", entry.Attrs["command_type"])
+		assert.Equal(t, aggstoretest.ErrAggregateStoreCannotStoreAggregate, entry.Attrs["error"])
+	})
+}
+
+func TestDispatcherHandleWithToken(t *testing.T) {
+	t.Run("ItFailsIfPositionsAreNotTracked", func(t *testing.T) {
+		// arrange
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		d := createDispatcher(ID)
+
+		// act
+		_, _, err := d.HandleWithToken(aggtest.MakeSomethingHappen{AggID: ID})
+
+		// assert
+		assert.ErrorIs(t, err, dispatcher.ErrPositionsNotTracked)
+	})
+
+	t.Run("ItFailsIfItCannotHandleTheCommand", func(t *testing.T) {
+		// arrange
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		d := createDispatcher(ID,
+			withAggregateStoreSaveErr(aggstoretest.ErrAggregateStoreCannotStoreAggregate),
+			withPositions(positionReaderFunc(func() (int, error) { return 1, nil })),
+		)
+
+		// act
+		_, _, err := d.HandleWithToken(aggtest.MakeSomethingHappen{AggID: ID})
+
+		// assert
+		assert.ErrorIs(t, err, aggstoretest.ErrAggregateStoreCannotStoreAggregate)
+	})
+
+	t.Run("ItFailsIfItCannotReadThePosition", func(t *testing.T) {
+		// arrange
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		d := createDispatcher(ID,
+			withPositions(positionReaderFunc(func() (int, error) { return 0, errCannotReadPosition })),
+		)
+
+		// act
+		_, _, err := d.HandleWithToken(aggtest.MakeSomethingHappen{AggID: ID})
+
+		// assert
+		assert.ErrorIs(t, err, errCannotReadPosition)
+	})
+
+	t.Run("ItReturnsTheConsistencyToken", func(t *testing.T) {
+		// arrange
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		d := createDispatcher(ID,
+			withLoadedEvents([]cqrs.DomainEvent{aggtest.SomethingElseHappened{}}),
+			withPositions(positionReaderFunc(func() (int, error) { return 42, nil })),
+		)
+
+		// act
+		events, token, err := d.HandleWithToken(aggtest.MakeSomethingHappen{AggID: ID})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, []cqrs.DomainEvent{aggtest.SomethingHappened{}}, events)
+		assert.Equal(t, x.ConsistencyToken{AggregateID: ID, Version: 2, Position: 42}, token)
+	})
+}
+
+func createAggregateFactory() *aggregate.Factory {
+	factory := aggregate.NewFactory()
+	factory.RegisterAggregate(aggtest.TestAggregateType, func(ID cqrs.Identifier) cqrs.ESAggregate {
+		return aggregate.FromAggregate(aggtest.NewTestAggregate(ID))
+	})
+
+	return factory
+}
+
+type positionReaderFunc func() (int, error)
+
+func (f positionReaderFunc) LastPosition() (int, error) {
+	return f()
+}
+
 type dispatcherOptions struct {
 	loadedEvents []cqrs.DomainEvent
 
 	loadErr  error
 	storeErr error
+
+	positions x.PositionReader
+
+	logger *slog.Logger
 }
 
 type option func(*dispatcherOptions)
@@ -88,6 +307,18 @@ func withLoadedEvents(loadedEvents []cqrs.DomainEvent) option {
 	}
 }
 
+func withPositions(positions x.PositionReader) option {
+	return func(o *dispatcherOptions) {
+		o.positions = positions
+	}
+}
+
+func withLogger(logger *slog.Logger) option {
+	return func(o *dispatcherOptions) {
+		o.logger = logger
+	}
+}
+
 func withAggregateStoreLoadErr(err error) option {
 	return func(o *dispatcherOptions) {
 		o.loadErr = err
@@ -119,8 +350,18 @@ func createDispatcher(id cqrs.Identifier, opts ...option) *dispatcher.Dispatcher
 		_ = esAgg.Apply(config.loadedEvents...)
 	}
 
+	var dispatcherOpts []dispatcher.Option
+	if config.positions != nil {
+		dispatcherOpts = append(dispatcherOpts, dispatcher.WithPositionReader(config.positions))
+	}
+
+	if config.logger != nil {
+		dispatcherOpts = append(dispatcherOpts, dispatcher.WithLogger(config.logger))
+	}
+
 	return dispatcher.NewDispatcher(
 		createAggregateStoreMock(esAgg, config.loadErr, config.storeErr),
+		dispatcherOpts...,
 	)
 }
 
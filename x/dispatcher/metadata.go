@@ -0,0 +1,112 @@
+package dispatcher
+
+import (
+	"reflect"
+
+	"github.com/screwyprof/cqrs"
+	"github.com/screwyprof/cqrs/x"
+)
+
+var metadataType = reflect.TypeFor[x.EventMetadata]() //nolint:gochecknoglobals
+
+// stampMetadata fills in the metadata of the produced events.
+//
+// The aggregate records the events as they are produced, so if it tracks its changes,
+// it is replaced with a view of itself which reports the stamped changes to the aggregate store.
+func (d *Dispatcher) stampMetadata(
+	agg cqrs.ESAggregate, events []cqrs.DomainEvent, traceParent string,
+) (cqrs.ESAggregate, []cqrs.DomainEvent) {
+	if d.clock == nil && d.ids == nil && traceParent == "" {
+		return agg, events
+	}
+
+	tracker, ok := agg.(x.ChangeTracker)
+	if !ok || len(tracker.Changes()) < len(events) {
+		return agg, d.stampEach(events, traceParent)
+	}
+
+	changes := d.stampEach(tracker.Changes(), traceParent)
+
+	stamped := &stampedAggregate{ESAggregate: agg, ChangeTracker: tracker, changes: changes}
+
+	return stamped, changes[len(changes)-len(events):]
+}
+
+func (d *Dispatcher) stampEach(events []cqrs.DomainEvent, traceParent string) []cqrs.DomainEvent {
+	stamped := make([]cqrs.DomainEvent, 0, len(events))
+	for _, e := range events {
+		stamped = append(stamped, d.stamp(e, traceParent))
+	}
+
+	return stamped
+}
+
+func (d *Dispatcher) stamp(e cqrs.DomainEvent, traceParent string) cqrs.DomainEvent {
+	carrier, ok := e.(x.MetadataCarrier)
+	if !ok {
+		return e
+	}
+
+	metadata := carrier.Metadata()
+
+	if metadata.EventID == "" && d.ids != nil {
+		metadata.EventID = d.ids.NewID()
+	}
+
+	if metadata.OccurredAt.IsZero() && d.clock != nil {
+		metadata.OccurredAt = d.clock.Now()
+	}
+
+	if metadata.TraceParent == "" {
+		metadata.TraceParent = traceParent
+	}
+
+	return withMetadata(e, metadata)
+}
+
+// withMetadata returns a copy of the event with the embedded x.EventMetadata set to the given one.
+func withMetadata(e cqrs.DomainEvent, metadata x.EventMetadata) cqrs.DomainEvent {
+	v := reflect.ValueOf(e)
+
+	isPointer := v.Kind() == reflect.Pointer
+	if isPointer {
+		if v.IsNil() {
+			return e
+		}
+
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return e
+	}
+
+	copied := reflect.New(v.Type())
+	copied.Elem().Set(v)
+
+	field := copied.Elem().FieldByName(metadataType.Name())
+	if !field.IsValid() || !field.CanSet() || field.Type() != metadataType {
+		return e
+	}
+
+	field.Set(reflect.ValueOf(metadata))
+
+	if isPointer {
+		return copied.Interface().(cqrs.DomainEvent) //nolint:forcetypeassert
+	}
+
+	return copied.Elem().Interface().(cqrs.DomainEvent) //nolint:forcetypeassert
+}
+
+// stampedAggregate reports the stamped changes of the aggregate to the aggregate store.
+type stampedAggregate struct {
+	cqrs.ESAggregate
+	x.ChangeTracker
+
+	changes []cqrs.DomainEvent
+}
+
+// Changes implements x.ChangeTracker interface.
+func (a *stampedAggregate) Changes() []cqrs.DomainEvent {
+	return a.changes
+}
@@ -0,0 +1,162 @@
+package dispatcher_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-faker/faker/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/cqrs"
+	"github.com/screwyprof/cqrs/aggregate"
+	"github.com/screwyprof/cqrs/aggregate/aggtest"
+	"github.com/screwyprof/cqrs/x"
+	"github.com/screwyprof/cqrs/x/aggstore"
+	"github.com/screwyprof/cqrs/x/clock"
+	"github.com/screwyprof/cqrs/x/dispatcher"
+	"github.com/screwyprof/cqrs/x/eventbus/evnbustest"
+	"github.com/screwyprof/cqrs/x/eventstore"
+	"github.com/screwyprof/cqrs/x/idgen"
+)
+
+func TestDispatcherMetadata(t *testing.T) {
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("ItStampsTheProducedEvents", func(t *testing.T) {
+		// arrange
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		data := faker.Word()
+
+		var published []cqrs.DomainEvent
+		eventStore := eventstore.NewInInMemoryEventStore(&evnbustest.EventPublisherMock{
+			Publisher: func(e ...cqrs.DomainEvent) error {
+				published = append(published, e...)
+
+				return nil
+			},
+		})
+
+		d := dispatcher.NewDispatcher(
+			aggstore.NewStore(eventStore, createStampingAggregateFactory(data, nil)),
+			dispatcher.WithClock(clock.NewFixed(now)),
+			dispatcher.WithIDGenerator(idgen.NewSequence("event")),
+		)
+
+		// act
+		got, err := d.Handle(aggtest.MakeSomethingHappen{AggID: ID})
+
+		// assert
+		want := []cqrs.DomainEvent{somethingStamped{
+			EventMetadata: x.EventMetadata{EventID: "event-1", OccurredAt: now},
+			Data:          data,
+		}}
+
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+		assert.Equal(t, want, published)
+
+		stored, err := eventStore.LoadEventsFor(ID)
+		assert.NoError(t, err)
+		assert.Equal(t, want, stored)
+	})
+
+	t.Run("ItKeepsTheMetadataSetByTheAggregate", func(t *testing.T) {
+		// arrange
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		data := faker.Word()
+
+		d := dispatcher.NewDispatcher(
+			aggstore.NewStore(
+				eventstore.NewInInMemoryEventStore(&evnbustest.EventPublisherMock{
+					Publisher: func(...cqrs.DomainEvent) error { return nil },
+				}),
+				createStampingAggregateFactory(data, idgen.NewFixed("set-by-aggregate")),
+			),
+			dispatcher.WithClock(clock.NewFixed(now)),
+			dispatcher.WithIDGenerator(idgen.NewSequence("event")),
+		)
+
+		// act
+		got, err := d.Handle(aggtest.MakeSomethingHappen{AggID: ID})
+
+		// assert
+		want := []cqrs.DomainEvent{somethingStamped{
+			EventMetadata: x.EventMetadata{EventID: "set-by-aggregate", OccurredAt: now},
+			Data:          data,
+		}}
+
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("ItLeavesTheEventsWithoutMetadataAsIs", func(t *testing.T) {
+		// arrange
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+
+		d := dispatcher.NewDispatcher(
+			aggstore.NewStore(
+				eventstore.NewInInMemoryEventStore(&evnbustest.EventPublisherMock{
+					Publisher: func(...cqrs.DomainEvent) error { return nil },
+				}),
+				createAggregateFactory(),
+			),
+			dispatcher.WithClock(clock.NewFixed(now)),
+			dispatcher.WithIDGenerator(idgen.NewSequence("event")),
+		)
+
+		// act
+		got, err := d.Handle(aggtest.MakeSomethingHappen{AggID: ID})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, []cqrs.DomainEvent{aggtest.SomethingHappened{}}, got)
+	})
+}
+
+// somethingStamped is an event which carries metadata.
+type somethingStamped struct {
+	x.EventMetadata
+
+	Data string
+}
+
+func (e somethingStamped) EventType() string {
+	return "SomethingStamped"
+}
+
+// stampingAggregate produces the events which carry metadata.
+//
+// The identifier generator is injected, so that the aggregate can set the event identifiers itself.
+type stampingAggregate struct {
+	id   cqrs.Identifier
+	data string
+	ids  x.IDGenerator
+}
+
+func (a *stampingAggregate) AggregateID() cqrs.Identifier {
+	return a.id
+}
+
+func (a *stampingAggregate) AggregateType() string {
+	return aggtest.TestAggregateType
+}
+
+func (a *stampingAggregate) MakeSomethingHappen(_ aggtest.MakeSomethingHappen) ([]cqrs.DomainEvent, error) {
+	e := somethingStamped{Data: a.data}
+	if a.ids != nil {
+		e.EventID = a.ids.NewID()
+	}
+
+	return []cqrs.DomainEvent{e}, nil
+}
+
+func (a *stampingAggregate) OnSomethingStamped(_ somethingStamped) {}
+
+func createStampingAggregateFactory(data string, ids x.IDGenerator) *aggregate.Factory {
+	factory := aggregate.NewFactory()
+	factory.RegisterAggregate(aggtest.TestAggregateType, func(ID cqrs.Identifier) cqrs.ESAggregate {
+		return aggregate.FromAggregate(&stampingAggregate{id: ID, data: data, ids: ids})
+	})
+
+	return factory
+}
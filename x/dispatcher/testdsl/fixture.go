@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/screwyprof/cqrs"
+	"github.com/screwyprof/cqrs/aggregate/aggtest/specdoc"
 )
 
 // GivenFn is a test init function.
@@ -23,6 +24,25 @@ type Checker func(got []cqrs.DomainEvent, err error)
 // DispatcherTester defines a dispatcher tester.
 type DispatcherTester func(given GivenFn, when WhenFn, then ThenFn)
 
+// PipelineTester defines a tester of the full write pipeline.
+type PipelineTester func(given *Pipeline, when WhenFn, then ThenFn)
+
+// Option configures the dispatcher tester.
+type Option func(*tester)
+
+type tester struct {
+	reporter *specdoc.Reporter
+}
+
+// WithReporter records the executed scenarios into the given reporter to produce living documentation.
+//
+// A scenario is documented under the aggregate type of its first command.
+func WithReporter(reporter *specdoc.Reporter) Option {
+	return func(t *tester) {
+		t.reporter = reporter
+	}
+}
+
 // Test runs the test.
 //
 // Example:
@@ -32,21 +52,85 @@ type DispatcherTester func(given GivenFn, when WhenFn, then ThenFn)
 //		  When(testdata.TestCommand{Param: "param"}),
 //		  Then(testdata.TestEvent{Data: "param"}),
 //	 )
-func Test(t *testing.T) DispatcherTester {
+func Test(t *testing.T, opts ...Option) DispatcherTester {
+	config := &tester{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
 	return func(given GivenFn, when WhenFn, then ThenFn) {
 		t.Helper()
-		then(t)(when(given()))
+
+		dispatcher := given()
+		if config.reporter == nil {
+			then(t)(when(dispatcher))
+
+			return
+		}
+
+		recorder := &commandRecorder{CommandHandler: dispatcher}
+
+		got, err := when(recorder)
+		then(t)(got, err)
+
+		config.reporter.Record(specdoc.Scenario{
+			Aggregate: recorder.aggregateType(),
+			Name:      specdoc.ScenarioName(t.Name()),
+			Given:     priorEvents(dispatcher),
+			When:      recorder.commands,
+			Then:      got,
+			Err:       err,
+			Failed:    t.Failed(),
+		})
 	}
 }
 
-// Given prepares the given aggregate for testing.
+// TestPipeline runs the test against the full write pipeline.
+//
+// Besides the Then checks, it asserts that exactly the events of the successfully handled commands are published,
+// so the events of the commands handled before a failing one are expected to be published too.
+//
+// Example:
+//
+//	 TestPipeline(t)(
+//		  NewPipeline(factory).WithEvents(ID, testdata.TestEvent{Data: "param"}),
+//		  When(testdata.TestCommand{Param: "param"}),
+//		  Then(testdata.TestEvent{Data: "param"}),
+//	 )
+func TestPipeline(t *testing.T, opts ...Option) PipelineTester {
+	test := Test(t, opts...)
+
+	return func(given *Pipeline, when WhenFn, then ThenFn) {
+		t.Helper()
+
+		test(Given(given), when, thenPublished(given, then))
+	}
+}
+
+// thenPublished extends the checks with the assertion of the events published by the pipeline.
+func thenPublished(p *Pipeline, then ThenFn) ThenFn {
+	return func(t *testing.T) Checker {
+		check := then(t)
+
+		return func(got []cqrs.DomainEvent, err error) {
+			t.Helper()
+			check(got, err)
+
+			assert.Equal(t, p.handledEvents(), p.Published(), "published events")
+		}
+	}
+}
+
+// Given prepares the given dispatcher for testing.
+//
+// To test against the prior events of the aggregates use NewPipeline(...).WithEvents with TestPipeline.
 func Given(dispatcher cqrs.CommandHandler) GivenFn {
 	return func() cqrs.CommandHandler {
 		return dispatcher
 	}
 }
 
-// When prepares the command handler for the given command.
+// When prepares the command handler for the given commands.
 func When(cmd ...cqrs.Command) WhenFn {
 	return func(dispatcher cqrs.CommandHandler) ([]cqrs.DomainEvent, error) {
 		var events []cqrs.DomainEvent
@@ -61,7 +145,7 @@ func When(cmd ...cqrs.Command) WhenFn {
 	}
 }
 
-// Then asserts that the expected events are applied.
+// Then asserts that the expected events are returned.
 func Then(want ...cqrs.DomainEvent) ThenFn {
 	return func(t *testing.T) Checker {
 		return func(got []cqrs.DomainEvent, err error) {
@@ -77,7 +161,43 @@ func ThenFailWith(want error) ThenFn {
 	return func(t *testing.T) Checker {
 		return func(got []cqrs.DomainEvent, err error) {
 			t.Helper()
-			assert.Equal(t, want, err)
+			assert.ErrorIs(t, err, want)
 		}
 	}
 }
+
+// commandRecorder records the commands handled by the dispatcher.
+type commandRecorder struct {
+	cqrs.CommandHandler
+
+	commands []cqrs.Command
+}
+
+// Handle implements cqrs.CommandHandler interface.
+func (r *commandRecorder) Handle(c cqrs.Command) ([]cqrs.DomainEvent, error) {
+	r.commands = append(r.commands, c)
+
+	return r.CommandHandler.Handle(c)
+}
+
+func (r *commandRecorder) aggregateType() string {
+	if len(r.commands) == 0 {
+		return ""
+	}
+
+	return r.commands[0].AggregateType()
+}
+
+func priorEvents(dispatcher cqrs.CommandHandler) []cqrs.DomainEvent {
+	p, ok := dispatcher.(*Pipeline)
+	if !ok {
+		return nil
+	}
+
+	var events []cqrs.DomainEvent
+	for _, s := range p.history {
+		events = append(events, s.events...)
+	}
+
+	return events
+}
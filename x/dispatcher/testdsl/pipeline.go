@@ -0,0 +1,149 @@
+package testdsl
+
+import (
+	"sync"
+
+	"github.com/screwyprof/cqrs"
+	"github.com/screwyprof/cqrs/x/aggstore"
+	"github.com/screwyprof/cqrs/x/dispatcher"
+	"github.com/screwyprof/cqrs/x/eventstore"
+)
+
+// Pipeline is the full write pipeline under test.
+//
+// It dispatches the commands to the aggregates created by the factory, stores the events
+// in an in-memory event store and records the events published to the bus.
+type Pipeline struct {
+	factory cqrs.AggregateFactory
+	history []stream
+
+	seedOnce sync.Once
+	seedErr  error
+
+	eventStore *eventstore.InMemoryEventStore
+	dispatcher *dispatcher.Dispatcher
+
+	recorder *recorder
+
+	handled   []cqrs.DomainEvent
+	handledMu sync.Mutex
+}
+
+type stream struct {
+	aggregateID cqrs.Identifier
+	events      []cqrs.DomainEvent
+}
+
+// NewPipeline creates a new instance of Pipeline.
+func NewPipeline(factory cqrs.AggregateFactory) *Pipeline {
+	if factory == nil {
+		panic("factory is required")
+	}
+
+	rec := &recorder{}
+	eventStore := eventstore.NewInInMemoryEventStore(rec)
+
+	return &Pipeline{
+		factory:    factory,
+		eventStore: eventStore,
+		dispatcher: dispatcher.NewDispatcher(aggstore.NewStore(eventStore, factory)),
+		recorder:   rec,
+	}
+}
+
+// WithEvents adds the prior events of the given aggregate which are stored before the first command is handled.
+func (p *Pipeline) WithEvents(aggregateID cqrs.Identifier, events ...cqrs.DomainEvent) *Pipeline {
+	p.history = append(p.history, stream{aggregateID: aggregateID, events: events})
+
+	return p
+}
+
+// Handle implements cqrs.CommandHandler interface.
+//
+// The prior events are stored once, before the first command is handled.
+func (p *Pipeline) Handle(c cqrs.Command) ([]cqrs.DomainEvent, error) {
+	p.seedOnce.Do(func() {
+		p.seedErr = p.seed()
+	})
+
+	if p.seedErr != nil {
+		return nil, p.seedErr
+	}
+
+	events, err := p.dispatcher.Handle(c)
+	if err != nil {
+		return nil, err
+	}
+
+	p.handledMu.Lock()
+	defer p.handledMu.Unlock()
+
+	p.handled = append(p.handled, events...)
+
+	return events, nil
+}
+
+// Published implements EventRecorder interface.
+//
+// The prior events are not recorded.
+func (p *Pipeline) Published() []cqrs.DomainEvent {
+	return p.recorder.events()
+}
+
+// handledEvents returns the events produced by the successfully handled commands.
+func (p *Pipeline) handledEvents() []cqrs.DomainEvent {
+	p.handledMu.Lock()
+	defer p.handledMu.Unlock()
+
+	return p.handled
+}
+
+func (p *Pipeline) seed() error {
+	for _, s := range p.history {
+		stored, err := p.eventStore.LoadEventsFor(s.aggregateID)
+		if err != nil {
+			return err
+		}
+
+		if err := p.eventStore.StoreEventsFor(s.aggregateID, len(stored), s.events); err != nil {
+			return err
+		}
+	}
+
+	p.recorder.start()
+
+	return nil
+}
+
+// recorder records the published events once started.
+type recorder struct {
+	published []cqrs.DomainEvent
+	recording bool
+	mu        sync.Mutex
+}
+
+// Publish implements x.EventPublisher interface.
+func (r *recorder) Publish(events ...cqrs.DomainEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.recording {
+		r.published = append(r.published, events...)
+	}
+
+	return nil
+}
+
+func (r *recorder) start() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.recording = true
+}
+
+func (r *recorder) events() []cqrs.DomainEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.published
+}
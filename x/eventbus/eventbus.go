@@ -1,23 +1,61 @@
 package eventbus
 
 import (
+	"context"
+	"fmt"
+	"log/slog"
 	"sync"
 
 	"github.com/screwyprof/cqrs"
 	"github.com/screwyprof/cqrs/x"
+	"github.com/screwyprof/cqrs/x/logging"
+	"github.com/screwyprof/cqrs/x/tracing"
 )
 
 // InMemoryEventBus publishes events.
 type InMemoryEventBus struct {
 	eventHandlers   map[x.EventHandler]struct{}
 	eventHandlersMu sync.RWMutex
+
+	logger *slog.Logger
+	tracer tracing.Tracer
+}
+
+// Option configures InMemoryEventBus.
+type Option func(*InMemoryEventBus)
+
+// WithLogger sets the logger the published events are logged with.
+//
+// The published events are logged at debug level, the handler failures at error level.
+func WithLogger(logger *slog.Logger) Option {
+	return func(b *InMemoryEventBus) {
+		b.logger = logger
+	}
+}
+
+// WithTracer sets the tracer the deliveries are traced with.
+//
+// The delivery of an event to a handler is traced with a span which continues the trace of the event,
+// see x.EventMetadata.
+func WithTracer(tracer tracing.Tracer) Option {
+	return func(b *InMemoryEventBus) {
+		b.tracer = tracer
+	}
 }
 
 // NewInMemoryEventBus creates a new instance of InMemoryEventBus.
-func NewInMemoryEventBus() *InMemoryEventBus {
-	return &InMemoryEventBus{
+func NewInMemoryEventBus(opts ...Option) *InMemoryEventBus {
+	b := &InMemoryEventBus{
 		eventHandlers: make(map[x.EventHandler]struct{}),
+		logger:        logging.Discard(),
+		tracer:        tracing.Noop(),
 	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	return b
 }
 
 // Register registers event handler.
@@ -29,19 +67,37 @@ func (b *InMemoryEventBus) Register(h x.EventHandler) {
 }
 
 // Publish implements cqrs.EventPublisher interface.
+//
+// The events are delivered to the handlers registered by the time Publish is called,
+// so a handler may register other handlers while handling an event.
 func (b *InMemoryEventBus) Publish(events ...cqrs.DomainEvent) error {
-	b.eventHandlersMu.RLock()
-	defer b.eventHandlersMu.RUnlock()
+	handlers := b.handlers()
 
-	for h := range b.eventHandlers {
+	for _, h := range handlers {
 		if err := b.handleEvents(h, events...); err != nil {
 			return err
 		}
 	}
 
+	ctx := tracing.ContinueFrom(context.Background(), b.tracer, events...)
+	b.logger.LogAttrs(ctx, slog.LevelDebug, "events published",
+		logging.EventTypes(events), slog.Int("handlers", len(handlers)))
+
 	return nil
 }
 
+func (b *InMemoryEventBus) handlers() []x.EventHandler {
+	b.eventHandlersMu.RLock()
+	defer b.eventHandlersMu.RUnlock()
+
+	handlers := make([]x.EventHandler, 0, len(b.eventHandlers))
+	for h := range b.eventHandlers {
+		handlers = append(handlers, h)
+	}
+
+	return handlers
+}
+
 func (b *InMemoryEventBus) handleEvents(h x.EventHandler, events ...cqrs.DomainEvent) error {
 	for _, e := range events {
 		err := b.handleEventIfMatches(h.SubscribedTo(), h, e)
@@ -56,5 +112,20 @@ func (b *InMemoryEventBus) handleEventIfMatches(m cqrs.EventMatcher, h x.EventHa
 	if !m(e) {
 		return nil
 	}
-	return h.Handle(e)
+
+	handler := slog.String("handler", fmt.Sprintf("%T", h))
+
+	ctx := tracing.ContinueFrom(context.Background(), b.tracer, e)
+	ctx, span := b.tracer.Start(ctx, "cqrs.event.deliver", slog.String("event_type", e.EventType()), handler)
+
+	err := h.Handle(e)
+	tracing.End(span, err)
+
+	if err != nil {
+		b.logger.LogAttrs(ctx, slog.LevelError, "event handler failed", logging.Event(e), handler, logging.Err(err))
+
+		return err
+	}
+
+	return nil
 }
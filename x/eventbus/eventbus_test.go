@@ -1,15 +1,19 @@
 package eventbus_test
 
 import (
+	"log/slog"
 	"testing"
 
+	"github.com/go-faker/faker/v4"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/screwyprof/cqrs"
 	event "github.com/screwyprof/cqrs/aggregate/aggtest"
 	"github.com/screwyprof/cqrs/x"
 	"github.com/screwyprof/cqrs/x/eventbus"
+	"github.com/screwyprof/cqrs/x/eventbus/evnbustest"
 	"github.com/screwyprof/cqrs/x/eventhandler/evnhndtest"
+	"github.com/screwyprof/cqrs/x/logging/logtest"
 )
 
 // ensure that EventBus implements cqrs.EventPublisher interface.
@@ -75,3 +79,53 @@ func TestInMemoryEventBus_Publish(t *testing.T) {
 		assert.Equal(t, want, eventHandler.Happened)
 	})
 }
+
+func TestInMemoryEventBusLogging(t *testing.T) {
+	t.Run("ItLogsThePublishedEvents", func(t *testing.T) {
+		// arrange
+		logger, logs := logtest.NewLogger()
+
+		b := eventbus.NewInMemoryEventBus(eventbus.WithLogger(logger))
+		b.Register(&evnhndtest.EventHandlerMock{})
+
+		// act
+		err := b.Publish(event.SomethingHappened{}, event.SomethingElseHappened{})
+
+		// assert
+		assert.NoError(t, err)
+
+		entry, ok := logs.Find("events published")
+		assert.True(t, ok)
+		assert.Equal(t, slog.LevelDebug, entry.Level)
+		assert.Equal(t, []string{"SomethingHappened", "SomethingElseHappened"}, entry.Attrs["event_types"])
+		assert.Equal(t, int64(1), entry.Attrs["handlers"])
+	})
+
+	t.Run("ItLogsHandlerFailures", func(t *testing.T) {
+		// arrange
+		logger, logs := logtest.NewLogger()
+
+		b := eventbus.NewInMemoryEventBus(eventbus.WithLogger(logger))
+		b.Register(&evnhndtest.EventHandlerMock{Err: evnhndtest.ErrCannotHandleEvent})
+
+		// act
+		err := b.Publish(event.SomethingHappened{Data: faker.Word()})
+
+		// assert
+		assert.Error(t, err)
+
+		entry, ok := logs.Find("event handler failed")
+		assert.True(t, ok)
+		assert.Equal(t, slog.LevelError, entry.Level)
+		assert.Equal(t, "SomethingHappened", entry.Attrs["event_type"])
+		assert.Equal(t, "*evnhndtest.EventHandlerMock", entry.Attrs["handler"])
+		assert.Equal(t, evnhndtest.ErrCannotHandleEvent, entry.Attrs["error"])
+		assert.NotContains(t, entry.Attrs, "event", "the payload is not logged")
+	})
+}
+
+func TestInMemoryEventBusConformance(t *testing.T) {
+	evnbustest.RunConformance(t, func() evnbustest.EventBus {
+		return eventbus.NewInMemoryEventBus()
+	})
+}
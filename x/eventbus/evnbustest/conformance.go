@@ -0,0 +1,209 @@
+package evnbustest
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-faker/faker/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/cqrs"
+	"github.com/screwyprof/cqrs/aggregate/aggtest"
+	"github.com/screwyprof/cqrs/x"
+)
+
+const (
+	concurrentPublishers = 10
+	eventsPerPublisher   = 20
+	deadlockTimeout      = time.Second
+)
+
+var errHandlerFailed = errors.New("handler failed")
+
+// EventBus publishes events to the registered event handlers.
+type EventBus interface {
+	x.EventPublisher
+	Register(h x.EventHandler)
+}
+
+// Factory creates a new event bus without any handlers registered.
+type Factory func() EventBus
+
+// RunConformance checks that the event bus behaves the way an event bus is expected to.
+//
+// The contract is:
+//   - a handler receives only the events its SubscribedTo matcher accepts;
+//   - a handler receives the events in the order they were published;
+//   - Publish returns once the events are handled and reports a handler failure;
+//   - publishing without any handlers registered succeeds;
+//   - a handler may register another handler while handling an event,
+//     the new handler receives the events published after the registration;
+//   - events published concurrently are all delivered to every handler.
+func RunConformance(t *testing.T, factory Factory) {
+	t.Helper()
+
+	t.Run("ItDeliversOnlyTheMatchedEvents", func(t *testing.T) {
+		b := factory()
+
+		h := &handlerRecorder{matcher: cqrs.MatchEvent("SomethingHappened")}
+		b.Register(h)
+
+		want := aggtest.SomethingHappened{Data: faker.Word()}
+
+		assert.NoError(t, b.Publish(aggtest.SomethingElseHappened{}, want, aggtest.SomethingHappenedV2{}))
+		assert.Equal(t, []cqrs.DomainEvent{want}, h.events())
+	})
+
+	t.Run("ItDeliversTheEventsToEveryHandler", func(t *testing.T) {
+		b := factory()
+
+		first, second := &handlerRecorder{}, &handlerRecorder{}
+		b.Register(first)
+		b.Register(second)
+
+		want := []cqrs.DomainEvent{aggtest.SomethingHappened{Data: faker.Word()}, aggtest.SomethingElseHappened{}}
+
+		assert.NoError(t, b.Publish(want...))
+		assert.Equal(t, want, first.events())
+		assert.Equal(t, want, second.events())
+	})
+
+	t.Run("ItDeliversTheEventsInTheOrderTheyWerePublished", func(t *testing.T) {
+		b := factory()
+
+		h := &handlerRecorder{}
+		b.Register(h)
+
+		want := make([]cqrs.DomainEvent, 0, eventsPerPublisher)
+		for range eventsPerPublisher {
+			want = append(want, aggtest.SomethingHappened{Data: faker.UUIDHyphenated()})
+		}
+
+		assert.NoError(t, b.Publish(want[:eventsPerPublisher/2]...))
+		assert.NoError(t, b.Publish(want[eventsPerPublisher/2:]...))
+		assert.Equal(t, want, h.events())
+	})
+
+	t.Run("ItReportsHandlerFailures", func(t *testing.T) {
+		b := factory()
+		b.Register(&handlerRecorder{err: errHandlerFailed})
+
+		err := b.Publish(aggtest.SomethingHappened{})
+
+		assert.ErrorIs(t, err, errHandlerFailed)
+	})
+
+	t.Run("ItPublishesWithoutHandlers", func(t *testing.T) {
+		b := factory()
+
+		assert.NoError(t, b.Publish(aggtest.SomethingHappened{}))
+	})
+
+	t.Run("ItPublishesNoEvents", func(t *testing.T) {
+		b := factory()
+
+		h := &handlerRecorder{}
+		b.Register(h)
+
+		assert.NoError(t, b.Publish())
+		assert.Empty(t, h.events())
+	})
+
+	t.Run("ItAllowsRegisteringHandlersWhilePublishing", func(t *testing.T) {
+		b := factory()
+
+		late := &handlerRecorder{}
+
+		var once sync.Once
+		b.Register(&handlerRecorder{onHandle: func() {
+			once.Do(func() { b.Register(late) })
+		}})
+
+		published := make(chan error, 1)
+		go func() {
+			published <- b.Publish(aggtest.SomethingHappened{})
+		}()
+
+		select {
+		case err := <-published:
+			assert.NoError(t, err)
+		case <-time.After(deadlockTimeout):
+			t.Fatal("registering a handler while publishing deadlocked")
+		}
+
+		want := aggtest.SomethingElseHappened{}
+
+		assert.NoError(t, b.Publish(want))
+		assert.Equal(t, []cqrs.DomainEvent{want}, late.events())
+	})
+
+	t.Run("ItDeliversTheEventsPublishedConcurrently", func(t *testing.T) {
+		b := factory()
+
+		h := &handlerRecorder{}
+		b.Register(h)
+
+		var wg sync.WaitGroup
+		for range concurrentPublishers {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				for range eventsPerPublisher {
+					assert.NoError(t, b.Publish(aggtest.SomethingHappened{Data: faker.UUIDHyphenated()}))
+				}
+			}()
+		}
+
+		wg.Wait()
+
+		assert.Len(t, h.events(), concurrentPublishers*eventsPerPublisher)
+	})
+}
+
+// handlerRecorder records the handled events.
+type handlerRecorder struct {
+	matcher  cqrs.EventMatcher
+	err      error
+	onHandle func()
+
+	handled []cqrs.DomainEvent
+	mu      sync.Mutex
+}
+
+// SubscribedTo implements x.EventHandler interface.
+func (h *handlerRecorder) SubscribedTo() cqrs.EventMatcher {
+	if h.matcher != nil {
+		return h.matcher
+	}
+
+	return func(cqrs.DomainEvent) bool { return true }
+}
+
+// Handle implements x.EventHandler interface.
+func (h *handlerRecorder) Handle(e cqrs.DomainEvent) error {
+	if h.onHandle != nil {
+		h.onHandle()
+	}
+
+	if h.err != nil {
+		return h.err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.handled = append(h.handled, e)
+
+	return nil
+}
+
+func (h *handlerRecorder) events() []cqrs.DomainEvent {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.handled
+}
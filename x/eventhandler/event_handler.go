@@ -1,26 +1,69 @@
 package eventhandler
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"reflect"
-	"strings"
 	"sync"
+	"time"
 
 	"github.com/screwyprof/cqrs"
+	"github.com/screwyprof/cqrs/naming"
 	"github.com/screwyprof/cqrs/x"
+	"github.com/screwyprof/cqrs/x/logging"
 )
 
+// ErrHandlerAlreadyRegistered is raised when two handler methods are registered under the same routing key.
+var ErrHandlerAlreadyRegistered = errors.New("event handler is already registered")
+
 // EventHandler handles events.
 type EventHandler struct {
 	handlers   map[string]x.EventHandlerFunc
 	handlersMu sync.RWMutex
+
+	naming naming.Naming
+	logger *slog.Logger
+}
+
+// Option configures EventHandler.
+type Option func(*EventHandler)
+
+// WithNaming sets the convention used to route events to the handler methods.
+//
+// Only the event applier part of the naming.Naming is used, e.g. naming.ParameterTypeNaming
+// routes events by the Go type of the handler method parameter.
+func WithNaming(n naming.Naming) Option {
+	return func(h *EventHandler) {
+		h.naming = n
+	}
+}
+
+// WithLogger sets the logger the handled events are logged with.
+//
+// The handled events are logged at debug level, the failures at error level.
+func WithLogger(logger *slog.Logger) Option {
+	return func(h *EventHandler) {
+		h.logger = logger
+	}
 }
 
 // New creates new instance of New.
-func New() *EventHandler {
-	return &EventHandler{
+//
+// Events are routed to the "On" + EventType() methods unless WithNaming option is given.
+func New(opts ...Option) *EventHandler {
+	h := &EventHandler{
 		handlers: make(map[string]x.EventHandlerFunc),
+		naming:   naming.Default,
+		logger:   logging.Discard(),
 	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
 }
 
 // RegisterHandler registers an event handler for the given method.
@@ -32,12 +75,18 @@ func (h *EventHandler) RegisterHandler(method string, handler x.EventHandlerFunc
 
 // SubscribedTo implements cqrs.EventHandler interface.
 func (h *EventHandler) SubscribedTo() cqrs.EventMatcher {
-	subscribedTo := make([]string, 0, len(h.handlers))
-	for m := range h.handlers {
-		subscribedTo = append(subscribedTo, strings.TrimPrefix(m, "On"))
-	}
+	return func(e cqrs.DomainEvent) bool {
+		if e == nil {
+			return false
+		}
+
+		h.handlersMu.RLock()
+		defer h.handlersMu.RUnlock()
 
-	return cqrs.MatchAnyEventOf(subscribedTo...)
+		_, ok := h.handlers[h.naming.EventKey(e)]
+
+		return ok
+	}
 }
 
 // Handle implements cqrs.EventHandler interface.
@@ -45,36 +94,71 @@ func (h *EventHandler) Handle(e cqrs.DomainEvent) error {
 	h.handlersMu.RLock()
 	defer h.handlersMu.RUnlock()
 
-	handlerID := "On" + e.EventType()
+	handlerID := h.naming.EventKey(e)
 
-	handler, ok := h.handlers["On"+e.EventType()]
+	handler, ok := h.handlers[handlerID]
 	if !ok {
 		return fmt.Errorf("event handler for %s event is not found", handlerID)
 	}
 
-	return handler(e)
+	started := time.Now()
+	err := handler(e)
+
+	attrs := []slog.Attr{logging.Event(e), slog.String("handler", handlerID), logging.Duration(time.Since(started))}
+
+	if err != nil {
+		h.logger.LogAttrs(context.Background(), slog.LevelError, "event handler failed", append(attrs, logging.Err(err))...)
+
+		return err
+	}
+
+	h.logger.LogAttrs(context.Background(), slog.LevelDebug, "event handled", attrs...)
+
+	return nil
 }
 
 // RegisterHandlers registers all the event handlers found in .
+//
+// It panics with ErrHandlerAlreadyRegistered and registers nothing if a handler method is registered
+// under the key of another one, e.g. two methods accept the same event type routed with naming.ParameterTypeNaming.
 func (h *EventHandler) RegisterHandlers(entity interface{}) {
+	h.handlersMu.Lock()
+	defer h.handlersMu.Unlock()
+
+	handlers := make(map[string]x.EventHandlerFunc)
+
 	entityType := reflect.TypeOf(entity)
 	for i := 0; i < entityType.NumMethod(); i++ {
 		method := entityType.Method(i)
-		h.registerHandlerDynamically(method, entity)
-	}
-}
 
-func (h *EventHandler) registerHandlerDynamically(method reflect.Method, entity interface{}) {
-	if !strings.HasPrefix(method.Name, "On") {
-		return
+		key, ok := h.naming.EventApplierKey(method)
+		if !ok {
+			continue
+		}
+
+		_, registered := h.handlers[key]
+		_, found := handlers[key]
+
+		if registered || found {
+			panic(fmt.Errorf("%w: %s of %T", ErrHandlerAlreadyRegistered, key, entity))
+		}
+
+		handlers[key] = func(e cqrs.DomainEvent) error {
+			return h.invokeEventHandler(method, entity, e)
+		}
 	}
 
-	h.RegisterHandler(method.Name, func(e cqrs.DomainEvent) error {
-		return h.invokeEventHandler(method, entity, e)
-	})
+	for key, handler := range handlers {
+		h.handlers[key] = handler
+	}
 }
 
 func (h *EventHandler) invokeEventHandler(method reflect.Method, entity interface{}, e cqrs.DomainEvent) error {
+	if eventType, paramType := reflect.TypeOf(e), method.Type.In(1); !eventType.AssignableTo(paramType) {
+		return fmt.Errorf("%w: %s routed to %s which accepts %s",
+			naming.ErrTypeCollision, eventType, method.Name, paramType)
+	}
+
 	result := method.Func.Call([]reflect.Value{reflect.ValueOf(entity), reflect.ValueOf(e)})
 
 	resErr := result[0].Interface()
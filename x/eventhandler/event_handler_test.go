@@ -1,6 +1,7 @@
 package eventhandler_test
 
 import (
+	"log/slog"
 	"testing"
 
 	"github.com/go-faker/faker/v4"
@@ -8,9 +9,12 @@ import (
 
 	"github.com/screwyprof/cqrs"
 	event "github.com/screwyprof/cqrs/aggregate/aggtest"
+	"github.com/screwyprof/cqrs/naming"
 	"github.com/screwyprof/cqrs/x"
 	"github.com/screwyprof/cqrs/x/eventhandler"
 	"github.com/screwyprof/cqrs/x/eventhandler/evnhndtest"
+	. "github.com/screwyprof/cqrs/x/eventhandler/testdsl"
+	"github.com/screwyprof/cqrs/x/logging/logtest"
 )
 
 // ensure that event handler implements cqrs.EventHandler interface.
@@ -66,6 +70,122 @@ func TestEventHandlerHandle(t *testing.T) {
 	})
 }
 
+func TestEventHandlerLogging(t *testing.T) {
+	t.Run("ItLogsTheHandledEvent", func(t *testing.T) {
+		// arrange
+		logger, logs := logtest.NewLogger()
+
+		s := eventhandler.New(eventhandler.WithLogger(logger))
+		s.RegisterHandlers(&evnhndtest.TestEventHandler{})
+
+		// act
+		err := s.Handle(event.SomethingHappened{Data: faker.Word()})
+
+		// assert
+		assert.NoError(t, err)
+
+		entry, ok := logs.Find("event handled")
+		assert.True(t, ok)
+		assert.Equal(t, slog.LevelDebug, entry.Level)
+		assert.Equal(t, "SomethingHappened", entry.Attrs["event_type"])
+		assert.Equal(t, "OnSomethingHappened", entry.Attrs["handler"])
+		assert.Contains(t, entry.Attrs, "duration")
+	})
+
+	t.Run("ItLogsTheFailure", func(t *testing.T) {
+		// arrange
+		logger, logs := logtest.NewLogger()
+
+		s := eventhandler.New(eventhandler.WithLogger(logger))
+		s.RegisterHandlers(&evnhndtest.TestEventHandler{})
+
+		// act
+		err := s.Handle(event.SomethingElseHappened{})
+
+		// assert
+		assert.Error(t, err)
+
+		entry, ok := logs.Find("event handler failed")
+		assert.True(t, ok)
+		assert.Equal(t, slog.LevelError, entry.Level)
+		assert.Equal(t, evnhndtest.ErrCannotHandleEvent, entry.Attrs["error"])
+	})
+}
+
+func TestEventHandlerWithParameterTypeNaming(t *testing.T) {
+	t.Run("ItHandlesTheGivenEventByItsType", func(t *testing.T) {
+		// arrange
+		eh := &evnhndtest.TestEventHandler{}
+
+		s := eventhandler.New(eventhandler.WithNaming(naming.ParameterTypeNaming{}))
+		s.RegisterHandlers(eh)
+
+		want := faker.Word()
+
+		// act
+		err := s.Handle(event.SomethingHappened{Data: want})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, want, eh.SomethingHappened)
+	})
+
+	t.Run("ItIsNotSubscribedToEventsOfOtherTypesSharingTheEventType", func(t *testing.T) {
+		// arrange
+		s := eventhandler.New(eventhandler.WithNaming(naming.ParameterTypeNaming{}))
+		s.RegisterHandlers(&evnhndtest.TestEventHandler{})
+
+		// act
+		matcher := s.SubscribedTo()
+
+		// assert
+		assert.True(t, matcher(event.SomethingHappened{}))
+		assert.False(t, matcher(event.SomethingHappenedElsewhere{}))
+	})
+}
+
+func TestEventHandlerTypeCollision(t *testing.T) {
+	t.Run("ItFailsIfAnEventOfAnotherTypeSharesTheEventType", func(t *testing.T) {
+		// arrange
+		s := eventhandler.New()
+		s.RegisterHandlers(&evnhndtest.TestEventHandler{})
+
+		// act
+		err := s.Handle(event.SomethingHappenedElsewhere{})
+
+		// assert
+		assert.ErrorIs(t, err, naming.ErrTypeCollision)
+	})
+}
+
+func TestEventHandlerRegisterHandlers(t *testing.T) {
+	t.Run("ItPanicsIfTwoMethodsHandleTheSameEventType", func(t *testing.T) {
+		// arrange
+		s := eventhandler.New(eventhandler.WithNaming(naming.ParameterTypeNaming{}))
+
+		// act
+		register := func() { s.RegisterHandlers(&duplicateEventHandler{}) }
+
+		// assert
+		assert.PanicsWithError(t, eventhandler.ErrHandlerAlreadyRegistered.Error()+
+			": github.com/screwyprof/cqrs/aggregate/aggtest.SomethingHappened of *eventhandler_test.duplicateEventHandler",
+			register)
+		assert.False(t, s.SubscribedTo()(event.SomethingHappened{}))
+	})
+
+	t.Run("ItPanicsIfTheEventIsAlreadyHandled", func(t *testing.T) {
+		// arrange
+		s := eventhandler.New()
+		s.RegisterHandlers(&evnhndtest.TestEventHandler{})
+
+		// act
+		register := func() { s.RegisterHandlers(&evnhndtest.TestEventHandler{}) }
+
+		// assert
+		assert.Panics(t, register)
+	})
+}
+
 func TestEventHandlerSubscribedTo(t *testing.T) {
 	t.Run("ItReturnersTheEventsItSubscribedTo", func(t *testing.T) {
 		// arrange
@@ -87,3 +207,60 @@ func TestEventHandlerSubscribedTo(t *testing.T) {
 		assert.True(t, matcher(event.SomethingElseHappened{}))
 	})
 }
+
+func TestEventHandlerProjection(t *testing.T) {
+	t.Run("ItProjectsTheEventsToTheReadModel", func(t *testing.T) {
+		first, second := faker.Word(), faker.Word()
+
+		readModel := &evnhndtest.TestEventHandler{}
+		projector := eventhandler.New()
+		projector.RegisterHandlers(readModel)
+
+		Test(t, projector, readModel)(
+			Given(event.SomethingHappened{Data: first}),
+			When(event.SomethingHappened{Data: second}),
+			Then(func(readModel *evnhndtest.TestEventHandler) {
+				assert.Equal(t, second, readModel.SomethingHappened)
+			}),
+		)
+	})
+
+	t.Run("ItSkipsTheEventsTheHandlerIsNotSubscribedTo", func(t *testing.T) {
+		want := faker.Word()
+
+		readModel := &evnhndtest.TestEventHandler{}
+		projector := eventhandler.New()
+		projector.RegisterHandlers(readModel)
+
+		Test(t, projector, readModel)(
+			Given(event.SomethingHappened{Data: want}),
+			When(event.SomethingImpossibleHappened{}),
+			Then(func(readModel *evnhndtest.TestEventHandler) {
+				assert.Equal(t, want, readModel.SomethingHappened)
+			}),
+		)
+	})
+
+	t.Run("ItFailsIfTheEventCannotBeProjected", func(t *testing.T) {
+		readModel := &evnhndtest.TestEventHandler{}
+		projector := eventhandler.New()
+		projector.RegisterHandlers(readModel)
+
+		Test(t, projector, readModel)(
+			Given(event.SomethingHappened{}),
+			When(event.SomethingElseHappened{}),
+			ThenFailWith(evnhndtest.ErrCannotHandleEvent),
+		)
+	})
+}
+
+// duplicateEventHandler handles the same event type twice.
+type duplicateEventHandler struct{}
+
+func (h *duplicateEventHandler) OnSomethingHappened(_ event.SomethingHappened) error {
+	return nil
+}
+
+func (h *duplicateEventHandler) OnSomethingHappenedAgain(_ event.SomethingHappened) error {
+	return nil
+}
@@ -0,0 +1,105 @@
+package testdsl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/cqrs"
+	"github.com/screwyprof/cqrs/x"
+	"github.com/screwyprof/cqrs/x/eventbus"
+)
+
+// GivenFn publishes the prior events.
+type GivenFn func(publisher x.EventPublisher) error
+
+// WhenFn publishes the events under test.
+type WhenFn func(publisher x.EventPublisher) error
+
+// ThenFn prepares the Checker.
+type ThenFn func(t *testing.T, readModel any) Checker
+
+// Checker asserts the given results.
+type Checker func(err error)
+
+// ProjectionTester defines a projection tester.
+type ProjectionTester func(given GivenFn, when WhenFn, then ThenFn)
+
+// Test runs the test against the event handler which projects the events to the read model.
+//
+// The events are published through an InMemoryEventBus the handler is registered with,
+// so only the events the handler is subscribed to reach it.
+// The test stops if the given events cannot be projected, only the error of the When events is checked.
+//
+// Example:
+//
+//	 projector := eventhandler.New()
+//	 projector.RegisterHandlers(NewTestProjector(readModel))
+//
+//	 Test(t, projector, readModel)(
+//		  Given(testdata.TestEvent{Data: "param"}),
+//		  When(testdata.OtherTestEvent{Data: "param"}),
+//		  Then(func(readModel *testdata.ReadModel) {
+//			  assert.Equal(t, "param", readModel.Data)
+//		  }),
+//	 )
+func Test(t *testing.T, handler x.EventHandler, readModel any) ProjectionTester {
+	return func(given GivenFn, when WhenFn, then ThenFn) {
+		t.Helper()
+
+		eventBus := eventbus.NewInMemoryEventBus()
+		eventBus.Register(handler)
+
+		if err := given(eventBus); err != nil {
+			t.Fatalf("given events cannot be projected: %v", err)
+		}
+
+		then(t, readModel)(when(eventBus))
+	}
+}
+
+// Given prepares the read model by projecting the given events.
+func Given(events ...cqrs.DomainEvent) GivenFn {
+	return func(publisher x.EventPublisher) error {
+		return publisher.Publish(events...)
+	}
+}
+
+// When projects the given events.
+func When(events ...cqrs.DomainEvent) WhenFn {
+	return func(publisher x.EventPublisher) error {
+		return publisher.Publish(events...)
+	}
+}
+
+// Then asserts that the events are projected and lets the check inspect the read model.
+//
+// The read model given to Test must be of type R.
+func Then[R any](check func(readModel R)) ThenFn {
+	return func(t *testing.T, readModel any) Checker {
+		return func(err error) {
+			t.Helper()
+
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			typed, ok := readModel.(R)
+			if !assert.True(t, ok, "read model %T is not of the expected type", readModel) {
+				return
+			}
+
+			check(typed)
+		}
+	}
+}
+
+// ThenFailWith asserts that the expected error occurred.
+func ThenFailWith(want error) ThenFn {
+	return func(t *testing.T, _ any) Checker {
+		return func(err error) {
+			t.Helper()
+			assert.ErrorIs(t, err, want)
+		}
+	}
+}
@@ -0,0 +1,268 @@
+package evnstoretest
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/go-faker/faker/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/cqrs"
+	"github.com/screwyprof/cqrs/aggregate/aggtest"
+	"github.com/screwyprof/cqrs/x"
+)
+
+const concurrentWriters = 10
+
+var errPublisherFailed = errors.New("publisher failed")
+
+// Factory creates a new empty event store which publishes the stored events with the given publisher.
+type Factory func(publisher x.EventPublisher) x.EventStore
+
+// RunConformance checks that the event store behaves the way x.EventStore is expected to.
+//
+// The contract is:
+//   - an unknown stream has no events;
+//   - the events are appended to the stream of their aggregate and loaded in the order they were appended;
+//   - the version is the number of events the stream is expected to have, an append at another
+//     version is rejected with an error wrapping x.ErrConcurrencyViolation and changes nothing;
+//   - out of concurrent appends at the same version exactly one succeeds;
+//   - the appended events are published once they are stored, the rejected ones are never published,
+//     and a publishing failure is returned as *x.PublishError, since the events are stored nevertheless.
+//
+// If the store implements x.MultiStreamEventStore, the atomicity of the multi-stream appends is checked as well.
+func RunConformance(t *testing.T, factory Factory) {
+	t.Helper()
+
+	t.Run("ItLoadsNoEventsForAnUnknownStream", func(t *testing.T) {
+		es := factory(&publisherRecorder{})
+
+		got, err := es.LoadEventsFor(newID())
+
+		assert.NoError(t, err)
+		assert.Empty(t, got)
+	})
+
+	t.Run("ItAppendsEventsToANewStream", func(t *testing.T) {
+		ID := newID()
+		es := factory(&publisherRecorder{})
+		want := []cqrs.DomainEvent{aggtest.SomethingHappened{Data: faker.Word()}, aggtest.SomethingElseHappened{}}
+
+		assert.NoError(t, es.StoreEventsFor(ID, 0, want))
+
+		assertStream(t, es, ID, want...)
+	})
+
+	t.Run("ItLoadsEventsInTheOrderTheyWereAppended", func(t *testing.T) {
+		ID := newID()
+		es := factory(&publisherRecorder{})
+
+		first := aggtest.SomethingHappened{Data: faker.Word()}
+		second := aggtest.SomethingElseHappened{}
+		third := aggtest.SomethingHappened{Data: faker.Word()}
+
+		assert.NoError(t, es.StoreEventsFor(ID, 0, []cqrs.DomainEvent{first}))
+		assert.NoError(t, es.StoreEventsFor(ID, 1, []cqrs.DomainEvent{second, third}))
+
+		assertStream(t, es, ID, first, second, third)
+	})
+
+	t.Run("ItKeepsStreamsApart", func(t *testing.T) {
+		firstID, secondID := newID(), newID()
+		es := factory(&publisherRecorder{})
+
+		first := aggtest.SomethingHappened{Data: faker.Word()}
+		second := aggtest.SomethingElseHappened{}
+
+		assert.NoError(t, es.StoreEventsFor(firstID, 0, []cqrs.DomainEvent{first}))
+		assert.NoError(t, es.StoreEventsFor(secondID, 0, []cqrs.DomainEvent{second}))
+
+		assertStream(t, es, firstID, first)
+		assertStream(t, es, secondID, second)
+	})
+
+	t.Run("ItAcceptsAnEmptyAppend", func(t *testing.T) {
+		ID := newID()
+		es := factory(&publisherRecorder{})
+		want := aggtest.SomethingHappened{Data: faker.Word()}
+
+		assert.NoError(t, es.StoreEventsFor(ID, 0, []cqrs.DomainEvent{want}))
+		assert.NoError(t, es.StoreEventsFor(ID, 1, nil))
+
+		assertStream(t, es, ID, want)
+	})
+
+	t.Run("ItRejectsAnAppendAtAStaleVersion", func(t *testing.T) {
+		ID := newID()
+		es := factory(&publisherRecorder{})
+		want := aggtest.SomethingHappened{Data: faker.Word()}
+
+		assert.NoError(t, es.StoreEventsFor(ID, 0, []cqrs.DomainEvent{want}))
+
+		err := es.StoreEventsFor(ID, 0, []cqrs.DomainEvent{aggtest.SomethingElseHappened{}})
+
+		assert.ErrorIs(t, err, x.ErrConcurrencyViolation)
+		assertStream(t, es, ID, want)
+	})
+
+	t.Run("ItRejectsAnAppendAtAVersionAheadOfTheStream", func(t *testing.T) {
+		ID := newID()
+		es := factory(&publisherRecorder{})
+
+		err := es.StoreEventsFor(ID, 1, []cqrs.DomainEvent{aggtest.SomethingHappened{}})
+
+		assert.ErrorIs(t, err, x.ErrConcurrencyViolation)
+		assertStream(t, es, ID)
+	})
+
+	t.Run("ItLetsExactlyOneOfTheConcurrentWritersAppend", func(t *testing.T) {
+		ID := newID()
+		es := factory(&publisherRecorder{})
+
+		errs := make(chan error, concurrentWriters)
+
+		var wg sync.WaitGroup
+		for range concurrentWriters {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				errs <- es.StoreEventsFor(ID, 0, []cqrs.DomainEvent{aggtest.SomethingHappened{Data: faker.Word()}})
+			}()
+		}
+
+		wg.Wait()
+		close(errs)
+
+		var succeeded int
+		for err := range errs {
+			if err == nil {
+				succeeded++
+
+				continue
+			}
+
+			assert.ErrorIs(t, err, x.ErrConcurrencyViolation)
+		}
+
+		assert.Equal(t, 1, succeeded)
+
+		got, err := es.LoadEventsFor(ID)
+		assert.NoError(t, err)
+		assert.Len(t, got, 1)
+	})
+
+	t.Run("ItPublishesTheAppendedEvents", func(t *testing.T) {
+		ID := newID()
+		publisher := &publisherRecorder{}
+		es := factory(publisher)
+
+		first := []cqrs.DomainEvent{aggtest.SomethingHappened{Data: faker.Word()}}
+		second := []cqrs.DomainEvent{aggtest.SomethingElseHappened{}}
+
+		assert.NoError(t, es.StoreEventsFor(ID, 0, first))
+		assert.NoError(t, es.StoreEventsFor(ID, 1, second))
+
+		assert.Equal(t, append(first, second...), publisher.events())
+	})
+
+	t.Run("ItDoesNotPublishTheRejectedEvents", func(t *testing.T) {
+		ID := newID()
+		publisher := &publisherRecorder{}
+		es := factory(publisher)
+
+		_ = es.StoreEventsFor(ID, 1, []cqrs.DomainEvent{aggtest.SomethingHappened{}})
+
+		assert.Empty(t, publisher.events())
+	})
+
+	t.Run("ItReportsPublishingFailures", func(t *testing.T) {
+		es := factory(&publisherRecorder{err: errPublisherFailed})
+
+		err := es.StoreEventsFor(newID(), 0, []cqrs.DomainEvent{aggtest.SomethingHappened{}})
+
+		var publishErr *x.PublishError
+		assert.ErrorAs(t, err, &publishErr)
+		assert.ErrorIs(t, err, errPublisherFailed)
+	})
+
+	t.Run("ItAppendsToSeveralStreamsAtomically", func(t *testing.T) {
+		publisher := &publisherRecorder{}
+
+		es, ok := factory(publisher).(x.MultiStreamEventStore)
+		if !ok {
+			t.Skip("the store does not implement x.MultiStreamEventStore")
+		}
+
+		firstID, secondID := newID(), newID()
+		first := aggtest.SomethingHappened{Data: faker.Word()}
+		second := aggtest.SomethingElseHappened{}
+
+		err := es.StoreEventsForMany(
+			x.StreamAppend{AggregateID: firstID, Version: 0, Events: []cqrs.DomainEvent{first}},
+			x.StreamAppend{AggregateID: secondID, Version: 1, Events: []cqrs.DomainEvent{second}},
+		)
+		assert.ErrorIs(t, err, x.ErrConcurrencyViolation)
+		assertStream(t, es, firstID)
+		assertStream(t, es, secondID)
+		assert.Empty(t, publisher.events())
+
+		err = es.StoreEventsForMany(
+			x.StreamAppend{AggregateID: firstID, Version: 0, Events: []cqrs.DomainEvent{first}},
+			x.StreamAppend{AggregateID: secondID, Version: 0, Events: []cqrs.DomainEvent{second}},
+		)
+		assert.NoError(t, err)
+		assertStream(t, es, firstID, first)
+		assertStream(t, es, secondID, second)
+		assert.Equal(t, []cqrs.DomainEvent{first, second}, publisher.events())
+	})
+}
+
+func assertStream(t *testing.T, es x.EventStore, ID cqrs.Identifier, want ...cqrs.DomainEvent) {
+	t.Helper()
+
+	got, err := es.LoadEventsFor(ID)
+	assert.NoError(t, err)
+
+	if len(want) == 0 {
+		assert.Empty(t, got)
+
+		return
+	}
+
+	assert.Equal(t, want, got)
+}
+
+func newID() cqrs.Identifier {
+	return aggtest.StringIdentifier(faker.UUIDHyphenated())
+}
+
+// publisherRecorder records the published events.
+type publisherRecorder struct {
+	published []cqrs.DomainEvent
+	err       error
+	mu        sync.Mutex
+}
+
+// Publish implements x.EventPublisher interface.
+func (p *publisherRecorder) Publish(events ...cqrs.DomainEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err != nil {
+		return p.err
+	}
+
+	p.published = append(p.published, events...)
+
+	return nil
+}
+
+func (p *publisherRecorder) events() []cqrs.DomainEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.published
+}
@@ -1,34 +1,90 @@
 package eventstore
 
 import (
-	"errors"
+	"context"
+	"log/slog"
+	"slices"
 	"sync"
 
 	"github.com/screwyprof/cqrs"
 	"github.com/screwyprof/cqrs/x"
+	"github.com/screwyprof/cqrs/x/logging"
+	"github.com/screwyprof/cqrs/x/tracing"
 )
 
 // ErrConcurrencyViolation happens if aggregate has been modified concurrently.
-var ErrConcurrencyViolation = errors.New("concurrency error: aggregate versions differ")
+// It is an alias of x.ErrConcurrencyViolation.
+var ErrConcurrencyViolation = x.ErrConcurrencyViolation
 
 // InMemoryEventStore stores and loads events from memory.
+//
+// It either publishes the stored events right away or records them in its outbox
+// to be delivered by outbox.Relay, see NewInMemoryEventStoreWithOutbox.
 type InMemoryEventStore struct {
 	eventStreams   map[cqrs.Identifier][]cqrs.DomainEvent
 	eventStreamsMu sync.RWMutex
 
 	eventPublisher x.EventPublisher
+
+	position int
+
+	outbox        []cqrs.DomainEvent
+	outboxPruned  int
+	outboxEnabled bool
+
+	logger *slog.Logger
+	tracer tracing.Tracer
+}
+
+// Option configures InMemoryEventStore.
+type Option func(*InMemoryEventStore)
+
+// WithLogger sets the logger the appended events are logged with.
+//
+// The appended events are logged at debug level, the concurrency violations at warn level
+// and the publishing failures at error level.
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *InMemoryEventStore) {
+		s.logger = logger
+	}
+}
+
+// WithTracer sets the tracer the appends are traced with.
+//
+// The span of an append continues the trace of the appended events, see x.EventMetadata.
+func WithTracer(tracer tracing.Tracer) Option {
+	return func(s *InMemoryEventStore) {
+		s.tracer = tracer
+	}
 }
 
 // NewInInMemoryEventStore creates a new instance of InMemoryEventStore.
-func NewInInMemoryEventStore(eventPublisher x.EventPublisher) *InMemoryEventStore {
+func NewInInMemoryEventStore(eventPublisher x.EventPublisher, opts ...Option) *InMemoryEventStore {
 	if eventPublisher == nil {
 		panic("eventPublisher is required")
 	}
 
-	return &InMemoryEventStore{
-		eventStreams:   make(map[cqrs.Identifier][]cqrs.DomainEvent),
-		eventPublisher: eventPublisher,
+	return newStore(&InMemoryEventStore{eventPublisher: eventPublisher}, opts...)
+}
+
+// NewInMemoryEventStoreWithOutbox creates a new instance of InMemoryEventStore which records the stored
+// events in the outbox instead of publishing them.
+//
+// The events are recorded atomically with the append, an outbox.Relay delivers them to a publisher.
+func NewInMemoryEventStoreWithOutbox(opts ...Option) *InMemoryEventStore {
+	return newStore(&InMemoryEventStore{outboxEnabled: true}, opts...)
+}
+
+func newStore(s *InMemoryEventStore, opts ...Option) *InMemoryEventStore {
+	s.eventStreams = make(map[cqrs.Identifier][]cqrs.DomainEvent)
+	s.logger = logging.Discard()
+	s.tracer = tracing.Noop()
+
+	for _, opt := range opts {
+		opt(s)
 	}
+
+	return s
 }
 
 // LoadEventsFor loads events for the given aggregate.
@@ -39,18 +95,162 @@ func (s *InMemoryEventStore) LoadEventsFor(aggregateID cqrs.Identifier) ([]cqrs.
 	return s.eventStreams[aggregateID], nil
 }
 
-// StoreEventsFor saves evens of the given aggregate.
+// StoreEventsFor appends events of the given aggregate to its stream.
+//
+// The version is the number of events the aggregate was loaded with.
+// It returns ErrConcurrencyViolation if the stream has been modified since then,
+// or *x.PublishError if the events are stored but cannot be published.
 func (s *InMemoryEventStore) StoreEventsFor(
 	aggregateID cqrs.Identifier, version int, events []cqrs.DomainEvent,
 ) error {
-	previousEvents, _ := s.LoadEventsFor(aggregateID)
-	if len(previousEvents) != version {
-		return ErrConcurrencyViolation
+	if err := s.tracedAppend(x.StreamAppend{AggregateID: aggregateID, Version: version, Events: events}); err != nil {
+		return err
+	}
+
+	return s.publish(events)
+}
+
+// StoreEventsForMany appends events to the streams of several aggregates atomically.
+//
+// It returns ErrConcurrencyViolation and stores nothing if any of the streams has been modified.
+// The events are published in the order of the appends once all of them are stored.
+func (s *InMemoryEventStore) StoreEventsForMany(appends ...x.StreamAppend) error {
+	if err := s.tracedAppend(appends...); err != nil {
+		return err
+	}
+
+	var events []cqrs.DomainEvent
+	for _, a := range appends {
+		events = append(events, a.Events...)
+	}
+
+	return s.publish(events)
+}
+
+// LastPosition implements x.PositionReader interface.
+func (s *InMemoryEventStore) LastPosition() (int, error) {
+	s.eventStreamsMu.RLock()
+	defer s.eventStreamsMu.RUnlock()
+
+	return s.position, nil
+}
+
+// MessagesAfter implements x.Outbox interface.
+//
+// It returns nothing unless the store has been created with NewInMemoryEventStoreWithOutbox.
+func (s *InMemoryEventStore) MessagesAfter(position, limit int) ([]x.OutboxMessage, error) {
+	s.eventStreamsMu.RLock()
+	defer s.eventStreamsMu.RUnlock()
+
+	start := max(position, s.outboxPruned) - s.outboxPruned
+	end := min(start+max(limit, 0), len(s.outbox))
+
+	var messages []x.OutboxMessage
+	for i := start; i < end; i++ {
+		messages = append(messages, x.OutboxMessage{Position: s.outboxPruned + i + 1, Event: s.outbox[i]})
+	}
+
+	return messages, nil
+}
+
+// Prune implements x.OutboxPruner interface.
+//
+// The positions of the messages left in the outbox do not change.
+func (s *InMemoryEventStore) Prune(position int) error {
+	s.eventStreamsMu.Lock()
+	defer s.eventStreamsMu.Unlock()
+
+	pruned := min(position-s.outboxPruned, len(s.outbox))
+	if pruned <= 0 {
+		return nil
+	}
+
+	s.outbox = slices.Clone(s.outbox[pruned:])
+	s.outboxPruned += pruned
+
+	return nil
+}
+
+func (s *InMemoryEventStore) publish(events []cqrs.DomainEvent) error {
+	if s.outboxEnabled {
+		return nil
+	}
+
+	if err := s.eventPublisher.Publish(events...); err != nil {
+		publishErr := &x.PublishError{Err: err}
+
+		ctx := tracing.ContinueFrom(context.Background(), s.tracer, events...)
+		s.logger.LogAttrs(ctx, slog.LevelError, "events cannot be published",
+			logging.EventTypes(events), logging.Err(publishErr))
+
+		return publishErr
+	}
+
+	return nil
+}
+
+func (s *InMemoryEventStore) tracedAppend(appends ...x.StreamAppend) error {
+	var events []cqrs.DomainEvent
+	for _, a := range appends {
+		events = append(events, a.Events...)
 	}
 
+	ctx := tracing.ContinueFrom(context.Background(), s.tracer, events...)
+	ctx, span := s.tracer.Start(ctx, "cqrs.events.append", slog.Int("streams", len(appends)), logging.EventTypes(events))
+
+	err := s.appendEvents(ctx, appends...)
+	tracing.End(span, err)
+
+	return err
+}
+
+func (s *InMemoryEventStore) appendEvents(ctx context.Context, appends ...x.StreamAppend) error {
 	s.eventStreamsMu.Lock()
 	defer s.eventStreamsMu.Unlock()
-	s.eventStreams[aggregateID] = events
 
-	return s.eventPublisher.Publish(events...)
+	pending := make(map[cqrs.Identifier][]cqrs.DomainEvent, len(appends))
+
+	for _, a := range appends {
+		previousEvents, ok := pending[a.AggregateID]
+		if !ok {
+			previousEvents = s.eventStreams[a.AggregateID]
+		}
+
+		if len(previousEvents) != a.Version {
+			s.logger.LogAttrs(ctx, slog.LevelWarn, "concurrency violation",
+				logging.AggregateID(a.AggregateID),
+				logging.Version(len(previousEvents)),
+				slog.Int("expected_version", a.Version),
+				logging.EventTypes(a.Events),
+			)
+
+			return ErrConcurrencyViolation
+		}
+
+		stream := make([]cqrs.DomainEvent, 0, len(previousEvents)+len(a.Events))
+		stream = append(stream, previousEvents...)
+		pending[a.AggregateID] = append(stream, a.Events...)
+	}
+
+	for aggregateID, stream := range pending {
+		s.eventStreams[aggregateID] = stream
+	}
+
+	for _, a := range appends {
+		s.position += len(a.Events)
+
+		s.logger.LogAttrs(ctx, slog.LevelDebug, "events appended",
+			logging.AggregateID(a.AggregateID),
+			logging.Version(a.Version+len(a.Events)),
+			logging.EventTypes(a.Events),
+		)
+	}
+
+	if s.outboxEnabled {
+		for _, a := range appends {
+			s.outbox = append(s.outbox, a.Events...)
+		}
+	}
+
+	return nil
 }
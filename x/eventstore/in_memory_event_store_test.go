@@ -1,6 +1,8 @@
 package eventstore_test
 
 import (
+	"errors"
+	"log/slog"
 	"testing"
 
 	"github.com/go-faker/faker/v4"
@@ -11,11 +13,27 @@ import (
 	"github.com/screwyprof/cqrs/x"
 	"github.com/screwyprof/cqrs/x/eventbus/evnbustest"
 	"github.com/screwyprof/cqrs/x/eventstore"
+	"github.com/screwyprof/cqrs/x/eventstore/evnstoretest"
+	"github.com/screwyprof/cqrs/x/logging/logtest"
 )
 
+var errCannotPublishEvents = errors.New("cannot publish events")
+
 // ensure that event aggstore implements cqrs.EventStore interface.
 var _ x.EventStore = (*eventstore.InMemoryEventStore)(nil)
 
+// ensure that event aggstore implements x.MultiStreamEventStore interface.
+var _ x.MultiStreamEventStore = (*eventstore.InMemoryEventStore)(nil)
+
+// ensure that event aggstore implements x.PositionReader interface.
+var _ x.PositionReader = (*eventstore.InMemoryEventStore)(nil)
+
+// ensure that event aggstore implements x.Outbox interface.
+var _ x.Outbox = (*eventstore.InMemoryEventStore)(nil)
+
+// ensure that event aggstore implements x.OutboxPruner interface.
+var _ x.OutboxPruner = (*eventstore.InMemoryEventStore)(nil)
+
 func TestNewInInMemoryEventStore(t *testing.T) {
 	t.Run("ItCreatesEventStore", func(t *testing.T) {
 		es := eventstore.NewInInMemoryEventStore(createEventPublisherMock(nil))
@@ -51,6 +69,24 @@ func TestInMemoryEventStoreLoadEventsFor(t *testing.T) {
 }
 
 func TestInMemoryEventStoreStoreEventsFor(t *testing.T) {
+	t.Run("ItAppendsEventsToTheStream", func(t *testing.T) {
+		// arrange
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		es := eventstore.NewInInMemoryEventStore(createEventPublisherMock(nil))
+
+		first := []cqrs.DomainEvent{aggtest.SomethingHappened{Data: faker.Word()}}
+		second := []cqrs.DomainEvent{aggtest.SomethingElseHappened{}, aggtest.SomethingHappened{Data: faker.Word()}}
+
+		// act
+		assert.NoError(t, es.StoreEventsFor(ID, 0, first))
+		assert.NoError(t, es.StoreEventsFor(ID, 1, second))
+
+		// assert
+		got, err := es.LoadEventsFor(ID)
+		assert.NoError(t, err)
+		assert.Equal(t, append(first, second...), got)
+	})
+
 	t.Run("ItReturnsConcurrencyErrorIfVersionsAreNotTheSame", func(t *testing.T) {
 		// arrange
 		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
@@ -73,3 +109,215 @@ func createEventPublisherMock(err error) *evnbustest.EventPublisherMock {
 
 	return eventPublisher
 }
+
+func TestInMemoryEventStoreStoreEventsForMany(t *testing.T) {
+	t.Run("ItAppendsEventsToAllTheStreamsAndPublishesThem", func(t *testing.T) {
+		// arrange
+		firstID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		secondID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+
+		var published []cqrs.DomainEvent
+		es := eventstore.NewInInMemoryEventStore(&evnbustest.EventPublisherMock{
+			Publisher: func(e ...cqrs.DomainEvent) error {
+				published = append(published, e...)
+
+				return nil
+			},
+		})
+
+		first := []cqrs.DomainEvent{aggtest.SomethingHappened{Data: faker.Word()}}
+		second := []cqrs.DomainEvent{aggtest.SomethingElseHappened{}}
+
+		// act
+		err := es.StoreEventsForMany(
+			x.StreamAppend{AggregateID: firstID, Version: 0, Events: first},
+			x.StreamAppend{AggregateID: secondID, Version: 0, Events: second},
+		)
+
+		// assert
+		assert.NoError(t, err)
+
+		got, _ := es.LoadEventsFor(firstID)
+		assert.Equal(t, first, got)
+
+		got, _ = es.LoadEventsFor(secondID)
+		assert.Equal(t, second, got)
+
+		assert.Equal(t, append(first, second...), published)
+	})
+
+	t.Run("ItStoresNothingIfAnyStreamHasBeenModified", func(t *testing.T) {
+		// arrange
+		firstID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		secondID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		es := eventstore.NewInInMemoryEventStore(createEventPublisherMock(nil))
+
+		// act
+		err := es.StoreEventsForMany(
+			x.StreamAppend{AggregateID: firstID, Version: 0, Events: []cqrs.DomainEvent{aggtest.SomethingHappened{}}},
+			x.StreamAppend{AggregateID: secondID, Version: 1, Events: []cqrs.DomainEvent{aggtest.SomethingHappened{}}},
+		)
+
+		// assert
+		assert.ErrorIs(t, err, eventstore.ErrConcurrencyViolation)
+
+		got, _ := es.LoadEventsFor(firstID)
+		assert.Empty(t, got)
+	})
+}
+
+func TestInMemoryEventStoreWithOutbox(t *testing.T) {
+	t.Run("ItRecordsTheStoredEventsInsteadOfPublishingThem", func(t *testing.T) {
+		// arrange
+		firstID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		secondID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		es := eventstore.NewInMemoryEventStoreWithOutbox()
+
+		first := aggtest.SomethingHappened{Data: faker.Word()}
+		second := aggtest.SomethingElseHappened{}
+
+		// act
+		assert.NoError(t, es.StoreEventsFor(firstID, 0, []cqrs.DomainEvent{first}))
+		assert.NoError(t, es.StoreEventsFor(secondID, 0, []cqrs.DomainEvent{second}))
+
+		// assert
+		got, err := es.MessagesAfter(0, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, []x.OutboxMessage{{Position: 1, Event: first}, {Position: 2, Event: second}}, got)
+
+		got, err = es.MessagesAfter(1, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, []x.OutboxMessage{{Position: 2, Event: second}}, got)
+	})
+
+	t.Run("ItPrunesTheMessagesKeepingThePositionsOfTheRest", func(t *testing.T) {
+		// arrange
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		es := eventstore.NewInMemoryEventStoreWithOutbox()
+
+		first := aggtest.SomethingHappened{Data: faker.Word()}
+		second := aggtest.SomethingElseHappened{}
+		assert.NoError(t, es.StoreEventsFor(ID, 0, []cqrs.DomainEvent{first, second}))
+
+		// act
+		err := es.Prune(1)
+
+		// assert
+		assert.NoError(t, err)
+
+		got, err := es.MessagesAfter(0, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, []x.OutboxMessage{{Position: 2, Event: second}}, got)
+
+		third := aggtest.SomethingHappened{Data: faker.Word()}
+		assert.NoError(t, es.StoreEventsFor(ID, 2, []cqrs.DomainEvent{third}))
+
+		got, err = es.MessagesAfter(2, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, []x.OutboxMessage{{Position: 3, Event: third}}, got)
+	})
+
+	t.Run("ItRecordsNothingIfTheAppendFails", func(t *testing.T) {
+		// arrange
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		es := eventstore.NewInMemoryEventStoreWithOutbox()
+
+		// act
+		err := es.StoreEventsFor(ID, 1, []cqrs.DomainEvent{aggtest.SomethingHappened{}})
+
+		// assert
+		assert.ErrorIs(t, err, eventstore.ErrConcurrencyViolation)
+
+		got, _ := es.MessagesAfter(0, 10)
+		assert.Empty(t, got)
+	})
+}
+
+func TestInMemoryEventStoreLastPosition(t *testing.T) {
+	t.Run("ItCountsTheEventsStoredInAllTheStreams", func(t *testing.T) {
+		// arrange
+		firstID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		secondID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		es := eventstore.NewInInMemoryEventStore(createEventPublisherMock(nil))
+
+		// act
+		assert.NoError(t, es.StoreEventsFor(firstID, 0, []cqrs.DomainEvent{aggtest.SomethingHappened{}}))
+		assert.NoError(t, es.StoreEventsForMany(
+			x.StreamAppend{AggregateID: firstID, Version: 1, Events: []cqrs.DomainEvent{aggtest.SomethingElseHappened{}}},
+			x.StreamAppend{AggregateID: secondID, Version: 0, Events: []cqrs.DomainEvent{aggtest.SomethingHappened{}}},
+		))
+		_ = es.StoreEventsFor(secondID, 0, []cqrs.DomainEvent{aggtest.SomethingHappened{}})
+
+		// assert
+		position, err := es.LastPosition()
+		assert.NoError(t, err)
+		assert.Equal(t, 3, position)
+	})
+}
+
+func TestInMemoryEventStoreLogging(t *testing.T) {
+	t.Run("ItLogsTheAppendedEvents", func(t *testing.T) {
+		// arrange
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		logger, logs := logtest.NewLogger()
+		es := eventstore.NewInInMemoryEventStore(createEventPublisherMock(nil), eventstore.WithLogger(logger))
+
+		// act
+		err := es.StoreEventsFor(ID, 0, []cqrs.DomainEvent{aggtest.SomethingHappened{Data: faker.Word()}})
+
+		// assert
+		assert.NoError(t, err)
+
+		entry, ok := logs.Find("events appended")
+		assert.True(t, ok)
+		assert.Equal(t, slog.LevelDebug, entry.Level)
+		assert.Equal(t, ID.String(), entry.Attrs["aggregate_id"])
+		assert.Equal(t, int64(1), entry.Attrs["version"])
+		assert.Equal(t, []string{"SomethingHappened"}, entry.Attrs["event_types"])
+	})
+
+	t.Run("ItLogsConcurrencyViolations", func(t *testing.T) {
+		// arrange
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		logger, logs := logtest.NewLogger()
+		es := eventstore.NewInInMemoryEventStore(createEventPublisherMock(nil), eventstore.WithLogger(logger))
+
+		// act
+		err := es.StoreEventsFor(ID, 1, []cqrs.DomainEvent{aggtest.SomethingHappened{}})
+
+		// assert
+		assert.ErrorIs(t, err, eventstore.ErrConcurrencyViolation)
+
+		entry, ok := logs.Find("concurrency violation")
+		assert.True(t, ok)
+		assert.Equal(t, slog.LevelWarn, entry.Level)
+		assert.Equal(t, int64(0), entry.Attrs["version"])
+		assert.Equal(t, int64(1), entry.Attrs["expected_version"])
+	})
+
+	t.Run("ItLogsPublishingFailures", func(t *testing.T) {
+		// arrange
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		logger, logs := logtest.NewLogger()
+		es := eventstore.NewInInMemoryEventStore(
+			createEventPublisherMock(errCannotPublishEvents), eventstore.WithLogger(logger),
+		)
+
+		// act
+		err := es.StoreEventsFor(ID, 0, []cqrs.DomainEvent{aggtest.SomethingHappened{}})
+
+		// assert
+		assert.Error(t, err)
+
+		entry, ok := logs.Find("events cannot be published")
+		assert.True(t, ok)
+		assert.Equal(t, slog.LevelError, entry.Level)
+		assert.Equal(t, err, entry.Attrs["error"])
+	})
+}
+
+func TestInMemoryEventStoreConformance(t *testing.T) {
+	evnstoretest.RunConformance(t, func(publisher x.EventPublisher) x.EventStore {
+		return eventstore.NewInInMemoryEventStore(publisher)
+	})
+}
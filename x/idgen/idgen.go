@@ -0,0 +1,62 @@
+// Package idgen provides the x.IDGenerator implementations: random UUIDs and the fakes for testing.
+package idgen
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+)
+
+// UUID generates random (version 4) UUIDs.
+type UUID struct{}
+
+// NewUUID creates a new instance of UUID.
+func NewUUID() UUID {
+	return UUID{}
+}
+
+// NewID implements x.IDGenerator interface.
+func (UUID) NewID() string {
+	var b [16]byte
+
+	_, _ = rand.Read(b[:]) // crypto/rand.Read never fails.
+
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // RFC 4122 variant
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// Fixed always generates the same identifier.
+type Fixed struct {
+	id string
+}
+
+// NewFixed creates a new instance of Fixed which generates the given identifier.
+func NewFixed(id string) Fixed {
+	return Fixed{id: id}
+}
+
+// NewID implements x.IDGenerator interface.
+func (g Fixed) NewID() string {
+	return g.id
+}
+
+// Sequence generates predictable identifiers made of a prefix and a counter, e.g. "event-1", "event-2".
+//
+// It is safe for concurrent use.
+type Sequence struct {
+	prefix string
+	next   atomic.Int64
+}
+
+// NewSequence creates a new instance of Sequence with the given prefix.
+func NewSequence(prefix string) *Sequence {
+	return &Sequence{prefix: prefix}
+}
+
+// NewID implements x.IDGenerator interface.
+func (g *Sequence) NewID() string {
+	return g.prefix + "-" + strconv.FormatInt(g.next.Add(1), 10)
+}
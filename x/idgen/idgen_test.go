@@ -0,0 +1,62 @@
+package idgen_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/cqrs/x"
+	"github.com/screwyprof/cqrs/x/idgen"
+)
+
+// ensure that the generators implement x.IDGenerator interface.
+var (
+	_ x.IDGenerator = idgen.UUID{}
+	_ x.IDGenerator = idgen.Fixed{}
+	_ x.IDGenerator = (*idgen.Sequence)(nil)
+)
+
+func TestUUID(t *testing.T) {
+	t.Run("ItGeneratesRandomVersion4UUIDs", func(t *testing.T) {
+		// arrange
+		g := idgen.NewUUID()
+		uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+		// act
+		first, second := g.NewID(), g.NewID()
+
+		// assert
+		assert.Regexp(t, uuid, first)
+		assert.Regexp(t, uuid, second)
+		assert.NotEqual(t, first, second)
+	})
+}
+
+func TestFixed(t *testing.T) {
+	t.Run("ItAlwaysGeneratesTheSameID", func(t *testing.T) {
+		// arrange
+		g := idgen.NewFixed("ID")
+
+		// act
+		first, second := g.NewID(), g.NewID()
+
+		// assert
+		assert.Equal(t, "ID", first)
+		assert.Equal(t, "ID", second)
+	})
+}
+
+func TestSequence(t *testing.T) {
+	t.Run("ItGeneratesPredictableIDs", func(t *testing.T) {
+		// arrange
+		g := idgen.NewSequence("event")
+
+		// act
+		first, second := g.NewID(), g.NewID()
+
+		// assert
+		assert.Equal(t, "event-1", first)
+		assert.Equal(t, "event-2", second)
+	})
+}
@@ -0,0 +1,145 @@
+package inbox
+
+import (
+	"sync"
+
+	"github.com/screwyprof/cqrs/x"
+)
+
+// InMemoryStore keeps the processed events in memory.
+//
+// It implements x.InboxStore, x.Transactor and StoreTransactor interfaces, a transaction runs exclusively
+// with respect to the other transactions, so that the same event cannot be processed twice concurrently.
+// The events marked through the store bound to a failed transaction are unmarked,
+// the events marked directly on the store are kept.
+type InMemoryStore struct {
+	processed   map[string]map[string]struct{}
+	processedMu sync.RWMutex
+
+	txMu sync.Mutex
+}
+
+// processedEvent is an event processed by the given handler.
+type processedEvent struct {
+	handlerID string
+	eventID   string
+}
+
+// NewInMemoryStore creates a new instance of InMemoryStore.
+func NewInMemoryStore() *InMemoryStore {
+	return &InMemoryStore{
+		processed: make(map[string]map[string]struct{}),
+	}
+}
+
+// Processed implements x.InboxStore interface.
+func (s *InMemoryStore) Processed(handlerID, eventID string) (bool, error) {
+	s.processedMu.RLock()
+	defer s.processedMu.RUnlock()
+
+	_, ok := s.processed[handlerID][eventID]
+
+	return ok, nil
+}
+
+// MarkProcessed implements x.InboxStore interface.
+func (s *InMemoryStore) MarkProcessed(handlerID, eventID string) error {
+	s.mark(handlerID, eventID)
+
+	return nil
+}
+
+// InTransaction implements x.Transactor interface.
+//
+// The function is run exclusively, use InStoreTransaction to unmark the events if it fails.
+func (s *InMemoryStore) InTransaction(fn func() error) error {
+	return s.InStoreTransaction(func(_ x.InboxStore) error {
+		return fn()
+	})
+}
+
+// InStoreTransaction implements StoreTransactor interface.
+//
+// The events marked through the given store are unmarked if fn returns an error or panics.
+func (s *InMemoryStore) InStoreTransaction(fn func(store x.InboxStore) error) error {
+	s.txMu.Lock()
+	defer s.txMu.Unlock()
+
+	tx := &inMemoryTx{store: s}
+
+	committed := false
+	defer func() {
+		if !committed {
+			tx.rollback()
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	committed = true
+
+	return nil
+}
+
+// mark records the processed event, it reports whether the event has not been processed before.
+func (s *InMemoryStore) mark(handlerID, eventID string) bool {
+	s.processedMu.Lock()
+	defer s.processedMu.Unlock()
+
+	if _, ok := s.processed[handlerID]; !ok {
+		s.processed[handlerID] = make(map[string]struct{})
+	}
+
+	if _, ok := s.processed[handlerID][eventID]; ok {
+		return false
+	}
+
+	s.processed[handlerID][eventID] = struct{}{}
+
+	return true
+}
+
+func (s *InMemoryStore) unmark(marks []processedEvent) {
+	s.processedMu.Lock()
+	defer s.processedMu.Unlock()
+
+	for _, mark := range marks {
+		delete(s.processed[mark.handlerID], mark.eventID)
+	}
+}
+
+// inMemoryTx is the store bound to a transaction, it remembers the events marked within it.
+type inMemoryTx struct {
+	store *InMemoryStore
+
+	marks   []processedEvent
+	marksMu sync.Mutex
+}
+
+// Processed implements x.InboxStore interface.
+func (tx *inMemoryTx) Processed(handlerID, eventID string) (bool, error) {
+	return tx.store.Processed(handlerID, eventID)
+}
+
+// MarkProcessed implements x.InboxStore interface.
+func (tx *inMemoryTx) MarkProcessed(handlerID, eventID string) error {
+	if !tx.store.mark(handlerID, eventID) {
+		return nil
+	}
+
+	tx.marksMu.Lock()
+	defer tx.marksMu.Unlock()
+
+	tx.marks = append(tx.marks, processedEvent{handlerID: handlerID, eventID: eventID})
+
+	return nil
+}
+
+func (tx *inMemoryTx) rollback() {
+	tx.marksMu.Lock()
+	defer tx.marksMu.Unlock()
+
+	tx.store.unmark(tx.marks)
+}
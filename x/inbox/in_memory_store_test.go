@@ -0,0 +1,160 @@
+package inbox_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/go-faker/faker/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/cqrs/x"
+	"github.com/screwyprof/cqrs/x/inbox"
+)
+
+// ensure that InMemoryStore implements x.InboxStore interface.
+var _ x.InboxStore = (*inbox.InMemoryStore)(nil)
+
+// ensure that InMemoryStore implements x.Transactor interface.
+var _ x.Transactor = (*inbox.InMemoryStore)(nil)
+
+// ensure that InMemoryStore implements inbox.StoreTransactor interface.
+var _ inbox.StoreTransactor = (*inbox.InMemoryStore)(nil)
+
+func TestInMemoryStore(t *testing.T) {
+	t.Run("ItRecordsTheProcessedEventsPerHandler", func(t *testing.T) {
+		// arrange
+		store := inbox.NewInMemoryStore()
+		eventID := faker.UUIDHyphenated()
+
+		// act
+		err := store.MarkProcessed("first", eventID)
+
+		// assert
+		assert.NoError(t, err)
+
+		processed, err := store.Processed("first", eventID)
+		assert.NoError(t, err)
+		assert.True(t, processed)
+
+		processed, err = store.Processed("second", eventID)
+		assert.NoError(t, err)
+		assert.False(t, processed)
+	})
+
+	t.Run("ItRunsTransactionsOneAtATime", func(t *testing.T) {
+		// arrange
+		store := inbox.NewInMemoryStore()
+
+		var (
+			running, maxRunning int
+			wg                  sync.WaitGroup
+		)
+
+		// act
+		for range 10 {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				_ = store.InTransaction(func() error {
+					running++
+					maxRunning = max(maxRunning, running)
+					running--
+
+					return nil
+				})
+			}()
+		}
+
+		wg.Wait()
+
+		// assert
+		assert.Equal(t, 1, maxRunning)
+	})
+
+	t.Run("ItKeepsTheEventsMarkedInACommittedTransaction", func(t *testing.T) {
+		// arrange
+		store := inbox.NewInMemoryStore()
+		eventID := faker.UUIDHyphenated()
+
+		// act
+		err := store.InStoreTransaction(func(tx x.InboxStore) error {
+			return tx.MarkProcessed("first", eventID)
+		})
+
+		// assert
+		assert.NoError(t, err)
+
+		processed, err := store.Processed("first", eventID)
+		assert.NoError(t, err)
+		assert.True(t, processed)
+	})
+
+	t.Run("ItUnmarksTheEventsIfTheTransactionFails", func(t *testing.T) {
+		// arrange
+		store := inbox.NewInMemoryStore()
+		committedID, failedID := faker.UUIDHyphenated(), faker.UUIDHyphenated()
+
+		assert.NoError(t, store.MarkProcessed("first", committedID))
+
+		// act
+		err := store.InStoreTransaction(func(tx x.InboxStore) error {
+			assert.NoError(t, tx.MarkProcessed("first", committedID))
+			assert.NoError(t, tx.MarkProcessed("first", failedID))
+
+			return errTransactionFailed
+		})
+
+		// assert
+		assert.ErrorIs(t, err, errTransactionFailed)
+
+		processed, err := store.Processed("first", failedID)
+		assert.NoError(t, err)
+		assert.False(t, processed)
+
+		processed, err = store.Processed("first", committedID)
+		assert.NoError(t, err)
+		assert.True(t, processed)
+	})
+
+	t.Run("ItUnmarksTheEventsIfTheTransactionPanics", func(t *testing.T) {
+		// arrange
+		store := inbox.NewInMemoryStore()
+		eventID := faker.UUIDHyphenated()
+
+		// act
+		assert.Panics(t, func() {
+			_ = store.InStoreTransaction(func(tx x.InboxStore) error {
+				assert.NoError(t, tx.MarkProcessed("first", eventID))
+
+				panic("transaction failed")
+			})
+		})
+
+		// assert
+		processed, err := store.Processed("first", eventID)
+		assert.NoError(t, err)
+		assert.False(t, processed)
+	})
+
+	t.Run("ItKeepsTheEventsMarkedOutsideOfAFailedTransaction", func(t *testing.T) {
+		// arrange
+		store := inbox.NewInMemoryStore()
+		eventID := faker.UUIDHyphenated()
+
+		// act
+		err := store.InStoreTransaction(func(_ x.InboxStore) error {
+			assert.NoError(t, store.MarkProcessed("first", eventID))
+
+			return errTransactionFailed
+		})
+
+		// assert
+		assert.ErrorIs(t, err, errTransactionFailed)
+
+		processed, err := store.Processed("first", eventID)
+		assert.NoError(t, err)
+		assert.True(t, processed)
+	})
+}
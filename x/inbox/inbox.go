@@ -0,0 +1,159 @@
+// Package inbox provides an event handler decorator which skips the events that have already been processed.
+package inbox
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/screwyprof/cqrs"
+	"github.com/screwyprof/cqrs/x"
+)
+
+// ErrEventIDNotFound is returned when the identifier of an event cannot be determined.
+var ErrEventIDNotFound = errors.New("event identifier is not found")
+
+// IdentifiableEvent is an event which carries a unique identifier.
+type IdentifiableEvent interface {
+	cqrs.DomainEvent
+	EventID() string
+}
+
+// StoreTransactor runs a function in a transaction of the inbox store.
+//
+// Unlike x.Transactor it hands the function the store bound to the transaction,
+// so that only the events marked through it are unmarked if the transaction fails.
+type StoreTransactor interface {
+	InStoreTransaction(fn func(store x.InboxStore) error) error
+}
+
+// EventIDFunc returns the unique identifier of the given event.
+type EventIDFunc func(e cqrs.DomainEvent) (string, error)
+
+// Handler decorates x.EventHandler so that each event is handled at most once per handler.
+//
+// With at-least-once delivery an event may be published several times, Handler records
+// the identifiers of the processed events in an x.InboxStore and skips the duplicates.
+type Handler struct {
+	handlerID string
+	handler   x.EventHandler
+	store     x.InboxStore
+
+	eventID    EventIDFunc
+	transactor x.Transactor
+}
+
+// Option configures Handler.
+type Option func(*Handler)
+
+// WithEventID sets the function the event identifiers are obtained with.
+//
+// By default the events are expected to implement IdentifiableEvent interface,
+// or to carry x.EventMetadata with a non-empty EventID.
+func WithEventID(eventID EventIDFunc) Option {
+	return func(h *Handler) {
+		h.eventID = eventID
+	}
+}
+
+// WithTransaction makes the handler check, handle and record the event in a single transaction.
+//
+// The transactor is expected to share the transaction between the inbox store and the read model,
+// so that the event is recorded as processed if and only if the read model has been updated.
+// Without a transaction the event is recorded right after it has been handled and
+// may be handled again if the process stops in between.
+//
+// If the transactor implements StoreTransactor, e.g. InMemoryStore, the event is checked and recorded
+// through the store bound to its transaction.
+func WithTransaction(transactor x.Transactor) Option {
+	return func(h *Handler) {
+		h.transactor = transactor
+	}
+}
+
+// NewHandler creates a new instance of Handler.
+//
+// The handlerID distinguishes the handlers sharing the same store, each of them processes every event once.
+func NewHandler(handlerID string, handler x.EventHandler, store x.InboxStore, opts ...Option) *Handler {
+	if handlerID == "" {
+		panic("handlerID is required")
+	}
+
+	if handler == nil {
+		panic("handler is required")
+	}
+
+	if store == nil {
+		panic("store is required")
+	}
+
+	h := &Handler{
+		handlerID: handlerID,
+		handler:   handler,
+		store:     store,
+		eventID:   identifiableEventID,
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// SubscribedTo implements x.EventHandler interface.
+func (h *Handler) SubscribedTo() cqrs.EventMatcher {
+	return h.handler.SubscribedTo()
+}
+
+// Handle implements x.EventHandler interface.
+//
+// It does nothing if the event has already been processed by this handler.
+func (h *Handler) Handle(e cqrs.DomainEvent) error {
+	eventID, err := h.eventID(e)
+	if err != nil {
+		return err
+	}
+
+	if h.transactor == nil {
+		return h.handleOnce(h.store, eventID, e)
+	}
+
+	if transactor, ok := h.transactor.(StoreTransactor); ok {
+		return transactor.InStoreTransaction(func(store x.InboxStore) error {
+			return h.handleOnce(store, eventID, e)
+		})
+	}
+
+	return h.transactor.InTransaction(func() error {
+		return h.handleOnce(h.store, eventID, e)
+	})
+}
+
+func (h *Handler) handleOnce(store x.InboxStore, eventID string, e cqrs.DomainEvent) error {
+	processed, err := store.Processed(h.handlerID, eventID)
+	if err != nil {
+		return err
+	}
+
+	if processed {
+		return nil
+	}
+
+	if err := h.handler.Handle(e); err != nil {
+		return err
+	}
+
+	return store.MarkProcessed(h.handlerID, eventID)
+}
+
+func identifiableEventID(e cqrs.DomainEvent) (string, error) {
+	if identifiable, ok := e.(IdentifiableEvent); ok {
+		return identifiable.EventID(), nil
+	}
+
+	if carrier, ok := e.(x.MetadataCarrier); ok && carrier.Metadata().EventID != "" {
+		return carrier.Metadata().EventID, nil
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrEventIDNotFound, e.EventType())
+}
@@ -0,0 +1,229 @@
+package inbox_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-faker/faker/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/cqrs"
+	"github.com/screwyprof/cqrs/aggregate/aggtest"
+	"github.com/screwyprof/cqrs/x"
+	"github.com/screwyprof/cqrs/x/eventhandler/evnhndtest"
+	"github.com/screwyprof/cqrs/x/inbox"
+)
+
+var errTransactionFailed = errors.New("transaction failed")
+
+// ensure that Handler implements x.EventHandler interface.
+var _ x.EventHandler = (*inbox.Handler)(nil)
+
+func TestNewHandler(t *testing.T) {
+	t.Run("ItPanicsIfHandlerIDIsNotGiven", func(t *testing.T) {
+		factory := func() {
+			inbox.NewHandler("", &evnhndtest.EventHandlerMock{}, inbox.NewInMemoryStore())
+		}
+		assert.Panics(t, factory)
+	})
+
+	t.Run("ItPanicsIfHandlerIsNotGiven", func(t *testing.T) {
+		factory := func() {
+			inbox.NewHandler(faker.Word(), nil, inbox.NewInMemoryStore())
+		}
+		assert.Panics(t, factory)
+	})
+
+	t.Run("ItPanicsIfStoreIsNotGiven", func(t *testing.T) {
+		factory := func() {
+			inbox.NewHandler(faker.Word(), &evnhndtest.EventHandlerMock{}, nil)
+		}
+		assert.Panics(t, factory)
+	})
+}
+
+func TestHandlerSubscribedTo(t *testing.T) {
+	t.Run("ItMatchesTheEventsTheDecoratedHandlerIsSubscribedTo", func(t *testing.T) {
+		// arrange
+		h := inbox.NewHandler(faker.Word(), &evnhndtest.EventHandlerMock{}, inbox.NewInMemoryStore())
+
+		// act
+		matcher := h.SubscribedTo()
+
+		// assert
+		assert.True(t, matcher(aggtest.SomethingHappened{}))
+		assert.False(t, matcher(aggtest.SomethingImpossibleHappened{}))
+	})
+}
+
+func TestHandlerHandle(t *testing.T) {
+	t.Run("ItSkipsTheAlreadyProcessedEvents", func(t *testing.T) {
+		// arrange
+		handler := &evnhndtest.EventHandlerMock{}
+		h := inbox.NewHandler(faker.Word(), handler, inbox.NewInMemoryStore(), inbox.WithEventID(eventIDByData))
+
+		e := aggtest.SomethingHappened{Data: faker.UUIDHyphenated()}
+
+		// act
+		assert.NoError(t, h.Handle(e))
+		assert.NoError(t, h.Handle(e))
+
+		// assert
+		assert.Equal(t, []cqrs.DomainEvent{e}, handler.Happened)
+	})
+
+	t.Run("ItTracksTheProcessedEventsPerHandler", func(t *testing.T) {
+		// arrange
+		store := inbox.NewInMemoryStore()
+
+		first := &evnhndtest.EventHandlerMock{}
+		second := &evnhndtest.EventHandlerMock{}
+
+		e := aggtest.SomethingHappened{Data: faker.UUIDHyphenated()}
+
+		// act
+		assert.NoError(t, inbox.NewHandler("first", first, store, inbox.WithEventID(eventIDByData)).Handle(e))
+		assert.NoError(t, inbox.NewHandler("second", second, store, inbox.WithEventID(eventIDByData)).Handle(e))
+
+		// assert
+		assert.Equal(t, []cqrs.DomainEvent{e}, first.Happened)
+		assert.Equal(t, []cqrs.DomainEvent{e}, second.Happened)
+	})
+
+	t.Run("ItUsesTheIdentifierOfIdentifiableEvents", func(t *testing.T) {
+		// arrange
+		handlerID := faker.Word()
+		store := inbox.NewInMemoryStore()
+		h := inbox.NewHandler(handlerID, &evnhndtest.EventHandlerMock{}, store)
+
+		ID := faker.UUIDHyphenated()
+
+		// act
+		err := h.Handle(somethingIdentifiableHappened{ID: ID})
+
+		// assert
+		assert.NoError(t, err)
+
+		processed, _ := store.Processed(handlerID, ID)
+		assert.True(t, processed)
+	})
+
+	t.Run("ItIdentifiesTheEventByItsMetadata", func(t *testing.T) {
+		// arrange
+		handlerID := faker.Word()
+		ID := faker.UUIDHyphenated()
+		store := inbox.NewInMemoryStore()
+		h := inbox.NewHandler(handlerID, &evnhndtest.EventHandlerMock{}, store)
+
+		// act
+		err := h.Handle(somethingWithMetadataHappened{EventMetadata: x.EventMetadata{EventID: ID}})
+
+		// assert
+		assert.NoError(t, err)
+
+		processed, _ := store.Processed(handlerID, ID)
+		assert.True(t, processed)
+	})
+
+	t.Run("ItFailsIfTheEventMetadataHasNoIdentifier", func(t *testing.T) {
+		// arrange
+		h := inbox.NewHandler(faker.Word(), &evnhndtest.EventHandlerMock{}, inbox.NewInMemoryStore())
+
+		// act
+		err := h.Handle(somethingWithMetadataHappened{})
+
+		// assert
+		assert.ErrorIs(t, err, inbox.ErrEventIDNotFound)
+	})
+
+	t.Run("ItFailsIfTheEventHasNoIdentifier", func(t *testing.T) {
+		// arrange
+		h := inbox.NewHandler(faker.Word(), &evnhndtest.EventHandlerMock{}, inbox.NewInMemoryStore())
+
+		// act
+		err := h.Handle(aggtest.SomethingHappened{})
+
+		// assert
+		assert.ErrorIs(t, err, inbox.ErrEventIDNotFound)
+	})
+
+	t.Run("ItDoesNotRecordTheEventIfTheHandlerFails", func(t *testing.T) {
+		// arrange
+		handlerID := faker.Word()
+		store := inbox.NewInMemoryStore()
+		handler := &evnhndtest.EventHandlerMock{Err: evnhndtest.ErrCannotHandleEvent}
+		h := inbox.NewHandler(handlerID, handler, store, inbox.WithEventID(eventIDByData))
+
+		e := aggtest.SomethingHappened{Data: faker.UUIDHyphenated()}
+
+		// act
+		err := h.Handle(e)
+
+		// assert
+		assert.ErrorIs(t, err, evnhndtest.ErrCannotHandleEvent)
+
+		processed, _ := store.Processed(handlerID, e.Data)
+		assert.False(t, processed)
+	})
+
+	t.Run("ItHandlesTheEventInTheGivenTransaction", func(t *testing.T) {
+		// arrange
+		handlerID := faker.Word()
+		store := inbox.NewInMemoryStore()
+		handler := &evnhndtest.EventHandlerMock{}
+
+		var inTransaction []cqrs.DomainEvent
+
+		transactor := transactorFunc(func(fn func() error) error {
+			if err := fn(); err != nil {
+				return err
+			}
+
+			inTransaction = handler.Happened
+
+			return errTransactionFailed
+		})
+
+		h := inbox.NewHandler(handlerID, handler, store,
+			inbox.WithEventID(eventIDByData), inbox.WithTransaction(transactor))
+
+		e := aggtest.SomethingHappened{Data: faker.UUIDHyphenated()}
+
+		// act
+		err := h.Handle(e)
+
+		// assert
+		assert.ErrorIs(t, err, errTransactionFailed)
+		assert.Equal(t, []cqrs.DomainEvent{e}, inTransaction)
+	})
+}
+
+type somethingIdentifiableHappened struct {
+	ID string
+}
+
+func (e somethingIdentifiableHappened) EventType() string {
+	return "SomethingIdentifiableHappened"
+}
+
+func (e somethingIdentifiableHappened) EventID() string {
+	return e.ID
+}
+
+type somethingWithMetadataHappened struct {
+	x.EventMetadata
+}
+
+func (e somethingWithMetadataHappened) EventType() string {
+	return "SomethingWithMetadataHappened"
+}
+
+type transactorFunc func(fn func() error) error
+
+func (f transactorFunc) InTransaction(fn func() error) error {
+	return f(fn)
+}
+
+func eventIDByData(e cqrs.DomainEvent) (string, error) {
+	return e.(aggtest.SomethingHappened).Data, nil //nolint:forcetypeassert
+}
@@ -0,0 +1,73 @@
+// Package logging provides the log/slog attributes the components log commands and events with.
+//
+// Only the types and identifiers of the commands and events are logged by default, since their payloads
+// may hold sensitive data. An event which implements slog.LogValuer is logged the way it describes itself,
+// so an event can opt in to exposing the fields which are safe to log.
+package logging
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/screwyprof/cqrs"
+)
+
+// Discard returns a logger which discards everything, the components use it unless a logger is given.
+func Discard() *slog.Logger {
+	return slog.New(slog.DiscardHandler)
+}
+
+// CommandType returns the attribute with the type of the command.
+func CommandType(c cqrs.Command) slog.Attr {
+	return slog.String("command_type", c.CommandType())
+}
+
+// AggregateID returns the attribute with the aggregate identifier.
+func AggregateID(id cqrs.Identifier) slog.Attr {
+	if id == nil {
+		return slog.String("aggregate_id", "")
+	}
+
+	return slog.String("aggregate_id", id.String())
+}
+
+// AggregateType returns the attribute with the aggregate type.
+func AggregateType(aggregateType string) slog.Attr {
+	return slog.String("aggregate_type", aggregateType)
+}
+
+// Version returns the attribute with the aggregate version.
+func Version(version int) slog.Attr {
+	return slog.Int("version", version)
+}
+
+// EventTypes returns the attribute with the types of the events.
+func EventTypes(events []cqrs.DomainEvent) slog.Attr {
+	types := make([]string, 0, len(events))
+	for _, e := range events {
+		types = append(types, e.EventType())
+	}
+
+	return slog.Any("event_types", types)
+}
+
+// Event returns the attribute describing the event.
+//
+// It holds the type of the event unless the event implements slog.LogValuer.
+func Event(e cqrs.DomainEvent) slog.Attr {
+	if valuer, ok := e.(slog.LogValuer); ok {
+		return slog.Any("event", valuer)
+	}
+
+	return slog.String("event_type", e.EventType())
+}
+
+// Duration returns the attribute with the time an operation took.
+func Duration(d time.Duration) slog.Attr {
+	return slog.Duration("duration", d)
+}
+
+// Err returns the attribute with the error.
+func Err(err error) slog.Attr {
+	return slog.Any("error", err)
+}
@@ -0,0 +1,80 @@
+package logging_test
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/cqrs"
+	"github.com/screwyprof/cqrs/aggregate/aggtest"
+	"github.com/screwyprof/cqrs/x/logging"
+)
+
+// somethingDescribed is an event which exposes the fields which are safe to log.
+type somethingDescribed struct {
+	Public string
+	Secret string
+}
+
+func (e somethingDescribed) EventType() string {
+	return "SomethingDescribed"
+}
+
+func (e somethingDescribed) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("type", e.EventType()), slog.String("public", e.Public))
+}
+
+func TestEvent(t *testing.T) {
+	t.Run("ItLogsTheEventTypeOnly", func(t *testing.T) {
+		// act
+		got := logging.Event(aggtest.SomethingHappened{Data: "secret"})
+
+		// assert
+		assert.Equal(t, slog.String("event_type", "SomethingHappened"), got)
+	})
+
+	t.Run("ItLogsTheEventTheWayItDescribesItself", func(t *testing.T) {
+		// act
+		got := logging.Event(somethingDescribed{Public: "public", Secret: "secret"})
+
+		// assert
+		assert.Equal(t, "event", got.Key)
+		assert.Equal(t, "[type=SomethingDescribed public=public]", got.Value.Resolve().String())
+	})
+}
+
+func TestEventTypes(t *testing.T) {
+	t.Run("ItLogsTheEventTypes", func(t *testing.T) {
+		// act
+		got := logging.EventTypes([]cqrs.DomainEvent{aggtest.SomethingHappened{Data: "secret"}, aggtest.SomethingElseHappened{}})
+
+		// assert
+		assert.Equal(t, "event_types", got.Key)
+		assert.Equal(t, []string{"SomethingHappened", "SomethingElseHappened"}, got.Value.Any())
+	})
+}
+
+func TestAggregateID(t *testing.T) {
+	t.Run("ItLogsTheIdentifier", func(t *testing.T) {
+		// act
+		got := logging.AggregateID(aggtest.StringIdentifier("ID"))
+
+		// assert
+		assert.Equal(t, slog.String("aggregate_id", "ID"), got)
+	})
+
+	t.Run("ItLogsAnEmptyIdentifierIfItIsNotGiven", func(t *testing.T) {
+		// act
+		got := logging.AggregateID(nil)
+
+		// assert
+		assert.Equal(t, slog.String("aggregate_id", ""), got)
+	})
+}
+
+func TestDiscard(t *testing.T) {
+	t.Run("ItDiscardsEverything", func(t *testing.T) {
+		assert.False(t, logging.Discard().Enabled(t.Context(), slog.LevelError))
+	})
+}
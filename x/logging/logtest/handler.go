@@ -0,0 +1,97 @@
+// Package logtest provides a slog.Handler which records the log entries for the assertions.
+package logtest
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// Entry is a recorded log entry.
+type Entry struct {
+	Level   slog.Level
+	Message string
+	Attrs   map[string]any
+}
+
+// Handler records the log entries of all levels.
+//
+// It is safe for concurrent use.
+type Handler struct {
+	entries *[]Entry
+	mu      *sync.Mutex
+	attrs   []slog.Attr
+}
+
+// NewHandler creates a new instance of Handler.
+func NewHandler() *Handler {
+	return &Handler{entries: &[]Entry{}, mu: &sync.Mutex{}}
+}
+
+// NewLogger creates a logger which records the entries into a new Handler.
+func NewLogger() (*slog.Logger, *Handler) {
+	h := NewHandler()
+
+	return slog.New(h), h
+}
+
+// Enabled implements slog.Handler interface.
+func (h *Handler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+// Handle implements slog.Handler interface.
+func (h *Handler) Handle(_ context.Context, r slog.Record) error {
+	entry := Entry{Level: r.Level, Message: r.Message, Attrs: make(map[string]any)}
+
+	for _, a := range h.attrs {
+		entry.Attrs[a.Key] = a.Value.Resolve().Any()
+	}
+
+	r.Attrs(func(a slog.Attr) bool {
+		entry.Attrs[a.Key] = a.Value.Resolve().Any()
+
+		return true
+	})
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	*h.entries = append(*h.entries, entry)
+
+	return nil
+}
+
+// WithAttrs implements slog.Handler interface.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &Handler{entries: h.entries, mu: h.mu, attrs: append(append([]slog.Attr{}, h.attrs...), attrs...)}
+}
+
+// WithGroup implements slog.Handler interface.
+//
+// The groups are ignored, the attributes are recorded under their own keys.
+func (h *Handler) WithGroup(string) slog.Handler {
+	return h
+}
+
+// Entries returns the recorded entries.
+func (h *Handler) Entries() []Entry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	entries := make([]Entry, len(*h.entries))
+	copy(entries, *h.entries)
+
+	return entries
+}
+
+// Find returns the first recorded entry with the given message.
+func (h *Handler) Find(message string) (Entry, bool) {
+	for _, e := range h.Entries() {
+		if e.Message == message {
+			return e, true
+		}
+	}
+
+	return Entry{}, false
+}
@@ -0,0 +1,34 @@
+package outbox
+
+import "sync"
+
+// InMemoryCheckpoint keeps the position of the last delivered message in memory.
+//
+// It is safe for concurrent use.
+type InMemoryCheckpoint struct {
+	position   int
+	positionMu sync.RWMutex
+}
+
+// NewInMemoryCheckpoint creates a new instance of InMemoryCheckpoint.
+func NewInMemoryCheckpoint() *InMemoryCheckpoint {
+	return &InMemoryCheckpoint{}
+}
+
+// Position implements x.Checkpoint interface.
+func (c *InMemoryCheckpoint) Position() (int, error) {
+	c.positionMu.RLock()
+	defer c.positionMu.RUnlock()
+
+	return c.position, nil
+}
+
+// Save implements x.Checkpoint interface.
+func (c *InMemoryCheckpoint) Save(position int) error {
+	c.positionMu.Lock()
+	defer c.positionMu.Unlock()
+
+	c.position = position
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package outbox_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/cqrs/x"
+	"github.com/screwyprof/cqrs/x/outbox"
+)
+
+// ensure that InMemoryCheckpoint implements x.Checkpoint interface.
+var _ x.Checkpoint = (*outbox.InMemoryCheckpoint)(nil)
+
+func TestInMemoryCheckpoint(t *testing.T) {
+	t.Run("ItStartsAtTheBeginningOfTheOutbox", func(t *testing.T) {
+		// act
+		position, err := outbox.NewInMemoryCheckpoint().Position()
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, 0, position)
+	})
+
+	t.Run("ItReturnsTheSavedPosition", func(t *testing.T) {
+		// arrange
+		checkpoint := outbox.NewInMemoryCheckpoint()
+
+		// act
+		err := checkpoint.Save(42)
+
+		// assert
+		assert.NoError(t, err)
+
+		position, _ := checkpoint.Position()
+		assert.Equal(t, 42, position)
+	})
+}
@@ -0,0 +1,246 @@
+// Package outbox delivers the events recorded in an outbox to an event publisher.
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/screwyprof/cqrs/x"
+)
+
+const (
+	defaultBatchSize    = 100
+	defaultMaxAttempts  = 3
+	defaultRetryDelay   = 100 * time.Millisecond
+	defaultPollInterval = time.Second
+)
+
+// ErrDeliveryFailed is returned when a message cannot be published after all the attempts.
+var ErrDeliveryFailed = errors.New("outbox message delivery failed")
+
+// DeadLetterFunc receives a message which cannot be published after all the attempts along with the failure.
+//
+// The relay skips the message and goes on with the next one if it returns nil,
+// otherwise it stops and returns the error.
+type DeadLetterFunc func(message x.OutboxMessage, err error) error
+
+// Relay delivers the outbox messages to an event publisher with at-least-once guarantees.
+//
+// The messages are published one by one in the order of their positions. The position of each
+// published message is saved to the checkpoint, so the relay resumes after the last delivered message.
+// A message may be published again if the relay stops between publishing it and saving the checkpoint,
+// hence the event handlers are expected to be idempotent.
+type Relay struct {
+	outbox         x.Outbox
+	eventPublisher x.EventPublisher
+	checkpoint     x.Checkpoint
+
+	batchSize    int
+	maxAttempts  int
+	retryDelay   time.Duration
+	pollInterval time.Duration
+
+	deadLetter DeadLetterFunc
+	pruning    bool
+}
+
+// Option configures Relay.
+type Option func(*Relay)
+
+// WithCheckpoint sets the checkpoint the delivery progress is tracked with.
+//
+// An InMemoryCheckpoint is used by default.
+func WithCheckpoint(checkpoint x.Checkpoint) Option {
+	return func(r *Relay) {
+		r.checkpoint = checkpoint
+	}
+}
+
+// WithBatchSize sets the maximum number of messages fetched from the outbox at once.
+//
+// It panics if the batch size is not positive.
+func WithBatchSize(batchSize int) Option {
+	if batchSize <= 0 {
+		panic("batchSize must be positive")
+	}
+
+	return func(r *Relay) {
+		r.batchSize = batchSize
+	}
+}
+
+// WithRetries sets how many times a message is published before giving up and the delay between attempts.
+func WithRetries(maxAttempts int, retryDelay time.Duration) Option {
+	return func(r *Relay) {
+		r.maxAttempts = maxAttempts
+		r.retryDelay = retryDelay
+	}
+}
+
+// WithDeadLetter sets where the messages which cannot be published after all the attempts go.
+//
+// By default the relay stops at such a message, so that it is never lost, and delivers nothing after it
+// until the message is published. With a dead letter the message is handed over to it and skipped,
+// e.g. a DeadLetterFunc which records the message for inspection and returns nil.
+func WithDeadLetter(deadLetter DeadLetterFunc) Option {
+	return func(r *Relay) {
+		r.deadLetter = deadLetter
+	}
+}
+
+// WithPruning makes the relay drop the delivered messages from the outbox, which must implement x.OutboxPruner.
+//
+// Use it only if the relay is the single consumer of the outbox.
+func WithPruning() Option {
+	return func(r *Relay) {
+		r.pruning = true
+	}
+}
+
+// WithPollInterval sets how often Run checks the outbox for new messages once it has delivered all of them.
+func WithPollInterval(pollInterval time.Duration) Option {
+	return func(r *Relay) {
+		r.pollInterval = pollInterval
+	}
+}
+
+// NewRelay creates a new instance of Relay.
+func NewRelay(outbox x.Outbox, eventPublisher x.EventPublisher, opts ...Option) *Relay {
+	if outbox == nil {
+		panic("outbox is required")
+	}
+
+	if eventPublisher == nil {
+		panic("eventPublisher is required")
+	}
+
+	r := &Relay{
+		outbox:         outbox,
+		eventPublisher: eventPublisher,
+		checkpoint:     NewInMemoryCheckpoint(),
+		batchSize:      defaultBatchSize,
+		maxAttempts:    defaultMaxAttempts,
+		retryDelay:     defaultRetryDelay,
+		pollInterval:   defaultPollInterval,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// Deliver publishes a batch of the messages recorded after the checkpoint and returns how many were delivered.
+//
+// It stops at the first message which cannot be published after all the attempts
+// and returns ErrDeliveryFailed, the messages published before it stay delivered.
+// If a dead letter is set, such a message is handed over to it and counted as delivered instead.
+func (r *Relay) Deliver(ctx context.Context) (int, error) {
+	position, err := r.checkpoint.Position()
+	if err != nil {
+		return 0, err
+	}
+
+	messages, err := r.outbox.MessagesAfter(position, r.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered, err := r.deliverAll(ctx, messages)
+	if delivered == 0 {
+		return 0, err
+	}
+
+	if pruneErr := r.prune(messages[delivered-1].Position); err == nil {
+		err = pruneErr
+	}
+
+	return delivered, err
+}
+
+func (r *Relay) deliverAll(ctx context.Context, messages []x.OutboxMessage) (int, error) {
+	for i, message := range messages {
+		if err := r.deliver(ctx, message); err != nil {
+			return i, err
+		}
+
+		if err := r.checkpoint.Save(message.Position); err != nil {
+			return i, err
+		}
+	}
+
+	return len(messages), nil
+}
+
+// deliver publishes the message, or hands it over to the dead letter if it cannot be published.
+func (r *Relay) deliver(ctx context.Context, message x.OutboxMessage) error {
+	err := r.publish(ctx, message)
+	if err == nil || r.deadLetter == nil || !errors.Is(err, ErrDeliveryFailed) {
+		return err
+	}
+
+	return r.deadLetter(message, err)
+}
+
+// prune drops the messages delivered up to the given position if the pruning is enabled.
+func (r *Relay) prune(position int) error {
+	pruner, ok := r.outbox.(x.OutboxPruner)
+	if !r.pruning || !ok {
+		return nil
+	}
+
+	return pruner.Prune(position)
+}
+
+// Run delivers the messages until the context is canceled.
+//
+// It returns the first delivery error, unless the message is handed over to the dead letter,
+// or the context error once the context is canceled.
+func (r *Relay) Run(ctx context.Context) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		delivered, err := r.Deliver(ctx)
+		if err != nil {
+			return err
+		}
+
+		if delivered > 0 {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(r.pollInterval):
+		}
+	}
+}
+
+func (r *Relay) publish(ctx context.Context, message x.OutboxMessage) error {
+	var err error
+
+	maxAttempts := max(r.maxAttempts, 1)
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if err = r.eventPublisher.Publish(message.Event); err == nil {
+			return nil
+		}
+
+		if attempt == maxAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(r.retryDelay):
+		}
+	}
+
+	return fmt.Errorf("%w: %s at position %d: %w", ErrDeliveryFailed, message.Event.EventType(), message.Position, err)
+}
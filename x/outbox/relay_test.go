@@ -0,0 +1,318 @@
+package outbox_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-faker/faker/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/cqrs"
+	"github.com/screwyprof/cqrs/aggregate/aggtest"
+	"github.com/screwyprof/cqrs/x"
+	"github.com/screwyprof/cqrs/x/eventbus/evnbustest"
+	"github.com/screwyprof/cqrs/x/eventstore"
+	"github.com/screwyprof/cqrs/x/outbox"
+)
+
+var errPublisherFailed = errors.New("publisher failed")
+
+func TestNewRelay(t *testing.T) {
+	t.Run("ItPanicsIfOutboxIsNotGiven", func(t *testing.T) {
+		factory := func() {
+			outbox.NewRelay(nil, nil)
+		}
+		assert.Panics(t, factory)
+	})
+
+	t.Run("ItPanicsIfEventPublisherIsNotGiven", func(t *testing.T) {
+		factory := func() {
+			outbox.NewRelay(eventstore.NewInMemoryEventStoreWithOutbox(), nil)
+		}
+		assert.Panics(t, factory)
+	})
+
+	t.Run("ItPanicsIfBatchSizeIsNotPositive", func(t *testing.T) {
+		factory := func() {
+			outbox.WithBatchSize(0)
+		}
+		assert.Panics(t, factory)
+	})
+}
+
+func TestRelayDeliver(t *testing.T) {
+	t.Run("ItPublishesTheRecordedEventsInOrder", func(t *testing.T) {
+		// arrange
+		es, want := createEventStoreWithEvents(t)
+		publisher, published := createEventPublisher(nil)
+
+		relay := outbox.NewRelay(es, publisher)
+
+		// act
+		delivered, err := relay.Deliver(context.Background())
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, len(want), delivered)
+		assert.Equal(t, want, *published)
+	})
+
+	t.Run("ItResumesAfterTheLastDeliveredMessage", func(t *testing.T) {
+		// arrange
+		es, want := createEventStoreWithEvents(t)
+		publisher, published := createEventPublisher(nil)
+		checkpoint := outbox.NewInMemoryCheckpoint()
+
+		relay := outbox.NewRelay(es, publisher, outbox.WithCheckpoint(checkpoint), outbox.WithBatchSize(1))
+
+		// act
+		first, err := relay.Deliver(context.Background())
+		assert.NoError(t, err)
+
+		rest, err := outbox.NewRelay(es, publisher, outbox.WithCheckpoint(checkpoint)).Deliver(context.Background())
+		assert.NoError(t, err)
+
+		// assert
+		assert.Equal(t, 1, first)
+		assert.Equal(t, len(want)-1, rest)
+		assert.Equal(t, want, *published)
+
+		position, _ := checkpoint.Position()
+		assert.Equal(t, len(want), position)
+	})
+
+	t.Run("ItRetriesFailedPublishing", func(t *testing.T) {
+		// arrange
+		es, want := createEventStoreWithEvents(t)
+
+		var published []cqrs.DomainEvent
+
+		failures := 2
+		publisher := &evnbustest.EventPublisherMock{
+			Publisher: func(e ...cqrs.DomainEvent) error {
+				if failures > 0 {
+					failures--
+
+					return errPublisherFailed
+				}
+
+				published = append(published, e...)
+
+				return nil
+			},
+		}
+
+		relay := outbox.NewRelay(es, publisher, outbox.WithRetries(3, time.Millisecond))
+
+		// act
+		_, err := relay.Deliver(context.Background())
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, want, published)
+	})
+
+	t.Run("ItKeepsTheMessageUndeliveredIfAllTheAttemptsFail", func(t *testing.T) {
+		// arrange
+		es, _ := createEventStoreWithEvents(t)
+		publisher, _ := createEventPublisher(errPublisherFailed)
+		checkpoint := outbox.NewInMemoryCheckpoint()
+
+		relay := outbox.NewRelay(es, publisher,
+			outbox.WithCheckpoint(checkpoint), outbox.WithRetries(2, time.Millisecond))
+
+		// act
+		delivered, err := relay.Deliver(context.Background())
+
+		// assert
+		assert.ErrorIs(t, err, outbox.ErrDeliveryFailed)
+		assert.ErrorIs(t, err, errPublisherFailed)
+		assert.Equal(t, 0, delivered)
+
+		position, _ := checkpoint.Position()
+		assert.Equal(t, 0, position)
+	})
+}
+
+func TestRelayDeadLetter(t *testing.T) {
+	t.Run("ItSkipsTheMessageHandedOverToTheDeadLetter", func(t *testing.T) {
+		// arrange
+		es, want := createEventStoreWithEvents(t)
+
+		var published []cqrs.DomainEvent
+		publisher := &evnbustest.EventPublisherMock{
+			Publisher: func(e ...cqrs.DomainEvent) error {
+				if e[0] == want[0] {
+					return errPublisherFailed
+				}
+
+				published = append(published, e...)
+
+				return nil
+			},
+		}
+
+		var deadLetters []x.OutboxMessage
+
+		relay := outbox.NewRelay(es, publisher, outbox.WithRetries(1, 0),
+			outbox.WithDeadLetter(func(message x.OutboxMessage, err error) error {
+				assert.ErrorIs(t, err, errPublisherFailed)
+
+				deadLetters = append(deadLetters, message)
+
+				return nil
+			}),
+		)
+
+		// act
+		delivered, err := relay.Deliver(context.Background())
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, len(want), delivered)
+		assert.Equal(t, []x.OutboxMessage{{Position: 1, Event: want[0]}}, deadLetters)
+		assert.Equal(t, want[1:], published)
+	})
+
+	t.Run("ItStopsIfTheDeadLetterFails", func(t *testing.T) {
+		// arrange
+		es, _ := createEventStoreWithEvents(t)
+		publisher, _ := createEventPublisher(errPublisherFailed)
+		errDeadLetterFailed := errors.New("dead letter failed")
+
+		relay := outbox.NewRelay(es, publisher, outbox.WithRetries(1, 0),
+			outbox.WithDeadLetter(func(x.OutboxMessage, error) error {
+				return errDeadLetterFailed
+			}),
+		)
+
+		// act
+		delivered, err := relay.Deliver(context.Background())
+
+		// assert
+		assert.ErrorIs(t, err, errDeadLetterFailed)
+		assert.Equal(t, 0, delivered)
+	})
+}
+
+func TestRelayPruning(t *testing.T) {
+	t.Run("ItPrunesTheDeliveredMessages", func(t *testing.T) {
+		// arrange
+		es, want := createEventStoreWithEvents(t)
+		publisher, published := createEventPublisher(nil)
+
+		relay := outbox.NewRelay(es, publisher, outbox.WithBatchSize(2), outbox.WithPruning())
+
+		// act
+		first, err := relay.Deliver(context.Background())
+		assert.NoError(t, err)
+
+		// assert
+		assert.Equal(t, 2, first)
+
+		messages, err := es.MessagesAfter(0, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, []x.OutboxMessage{{Position: 3, Event: want[2]}}, messages)
+
+		rest, err := relay.Deliver(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, rest)
+		assert.Equal(t, want, *published)
+	})
+
+	t.Run("ItKeepsTheDeliveredMessagesByDefault", func(t *testing.T) {
+		// arrange
+		es, want := createEventStoreWithEvents(t)
+		publisher, _ := createEventPublisher(nil)
+
+		// act
+		_, err := outbox.NewRelay(es, publisher).Deliver(context.Background())
+		assert.NoError(t, err)
+
+		// assert
+		messages, err := es.MessagesAfter(0, 10)
+		assert.NoError(t, err)
+		assert.Len(t, messages, len(want))
+	})
+}
+
+func TestRelayRun(t *testing.T) {
+	t.Run("ItDeliversTheMessagesUntilTheContextIsCanceled", func(t *testing.T) {
+		// arrange
+		es, want := createEventStoreWithEvents(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var published []cqrs.DomainEvent
+		publisher := &evnbustest.EventPublisherMock{
+			Publisher: func(e ...cqrs.DomainEvent) error {
+				published = append(published, e...)
+				if len(published) == len(want) {
+					cancel()
+				}
+
+				return nil
+			},
+		}
+
+		relay := outbox.NewRelay(es, publisher, outbox.WithPollInterval(time.Millisecond))
+
+		// act
+		err := relay.Run(ctx)
+
+		// assert
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, want, published)
+	})
+
+	t.Run("ItStopsOnDeliveryFailure", func(t *testing.T) {
+		// arrange
+		es, _ := createEventStoreWithEvents(t)
+		publisher, _ := createEventPublisher(errPublisherFailed)
+
+		relay := outbox.NewRelay(es, publisher, outbox.WithRetries(1, 0))
+
+		// act
+		err := relay.Run(context.Background())
+
+		// assert
+		assert.ErrorIs(t, err, outbox.ErrDeliveryFailed)
+	})
+}
+
+func createEventStoreWithEvents(t *testing.T) (*eventstore.InMemoryEventStore, []cqrs.DomainEvent) {
+	t.Helper()
+
+	firstID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+	secondID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+
+	first := []cqrs.DomainEvent{aggtest.SomethingHappened{Data: faker.Word()}, aggtest.SomethingElseHappened{}}
+	second := []cqrs.DomainEvent{aggtest.SomethingHappened{Data: faker.Word()}}
+
+	es := eventstore.NewInMemoryEventStoreWithOutbox()
+	assert.NoError(t, es.StoreEventsForMany(
+		x.StreamAppend{AggregateID: firstID, Version: 0, Events: first},
+		x.StreamAppend{AggregateID: secondID, Version: 0, Events: second},
+	))
+
+	return es, append(first, second...)
+}
+
+func createEventPublisher(err error) (*evnbustest.EventPublisherMock, *[]cqrs.DomainEvent) {
+	published := &[]cqrs.DomainEvent{}
+
+	return &evnbustest.EventPublisherMock{
+		Publisher: func(e ...cqrs.DomainEvent) error {
+			if err != nil {
+				return err
+			}
+
+			*published = append(*published, e...)
+
+			return nil
+		},
+	}, published
+}
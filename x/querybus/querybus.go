@@ -0,0 +1,135 @@
+// Package querybus routes queries to the handlers registered for their types.
+package querybus
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/screwyprof/cqrs"
+)
+
+var (
+	// ErrQueryHandlerNotFound is returned when no handler is registered for the query type.
+	ErrQueryHandlerNotFound = errors.New("query handler is not found")
+
+	// ErrUnexpectedQuery is returned when a handler receives a query of a type it does not accept.
+	ErrUnexpectedQuery = errors.New("unexpected query")
+
+	// ErrUnexpectedResult is returned when the result of a query is not of the requested type.
+	ErrUnexpectedResult = errors.New("unexpected query result")
+)
+
+// Middleware wraps a query handler, e.g. to log, cache or authorize queries.
+type Middleware func(next cqrs.QueryHandlerFunc) cqrs.QueryHandlerFunc
+
+// InMemoryQueryBus routes queries to the handlers registered by the query type.
+type InMemoryQueryBus struct {
+	handlers   map[string]cqrs.QueryHandlerFunc
+	handlersMu sync.RWMutex
+
+	middleware []Middleware
+}
+
+// Option configures InMemoryQueryBus.
+type Option func(*InMemoryQueryBus)
+
+// WithMiddleware wraps every query handler with the given middleware.
+//
+// The first middleware is the outermost one, i.e. it sees the query first and the result last.
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(b *InMemoryQueryBus) {
+		b.middleware = append(b.middleware, middleware...)
+	}
+}
+
+// NewInMemoryQueryBus creates a new instance of InMemoryQueryBus.
+func NewInMemoryQueryBus(opts ...Option) *InMemoryQueryBus {
+	b := &InMemoryQueryBus{
+		handlers: make(map[string]cqrs.QueryHandlerFunc),
+	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	return b
+}
+
+// RegisterHandler registers a query handler for the given query type.
+//
+// The handler is wrapped with the middleware once, when it is registered.
+func (b *InMemoryQueryBus) RegisterHandler(queryType string, handler cqrs.QueryHandlerFunc) {
+	for i := len(b.middleware) - 1; i >= 0; i-- {
+		handler = b.middleware[i](handler)
+	}
+
+	b.handlersMu.Lock()
+	defer b.handlersMu.Unlock()
+
+	b.handlers[queryType] = handler
+}
+
+// Handle implements cqrs.QueryHandler interface.
+func (b *InMemoryQueryBus) Handle(q cqrs.Query) (any, error) {
+	b.handlersMu.RLock()
+	handler, ok := b.handlers[q.QueryType()]
+	b.handlersMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrQueryHandlerNotFound, q.QueryType())
+	}
+
+	return handler(q)
+}
+
+// Register registers a typed query handler for the type of Q.
+//
+// The query type is taken from the zero value of Q, or of the type it points to if Q is a pointer type,
+// so QueryType must not depend on the fields of the query.
+func Register[Q cqrs.Query, R any](b *InMemoryQueryBus, handler func(Q) (R, error)) {
+	b.RegisterHandler(zeroQuery[Q]().QueryType(), func(q cqrs.Query) (any, error) {
+		query, ok := q.(Q)
+		if !ok {
+			return nil, fmt.Errorf("%w: %T routed to a handler of %s", ErrUnexpectedQuery, q, reflect.TypeFor[Q]())
+		}
+
+		return handler(query)
+	})
+}
+
+// zeroQuery returns a query of type Q whose QueryType can be called, i.e. not a nil pointer.
+func zeroQuery[Q cqrs.Query]() Q {
+	var zero Q
+
+	if t := reflect.TypeFor[Q](); t.Kind() == reflect.Pointer {
+		return reflect.New(t.Elem()).Interface().(Q) //nolint:forcetypeassert
+	}
+
+	return zero
+}
+
+// Ask sends the query to the handler and returns the result of the requested type.
+//
+// It returns ErrUnexpectedResult if the result is of another type.
+func Ask[R any](h cqrs.QueryHandler, q cqrs.Query) (R, error) {
+	var zero R
+
+	result, err := h.Handle(q)
+	if err != nil {
+		return zero, err
+	}
+
+	if result == nil {
+		return zero, nil
+	}
+
+	typed, ok := result.(R)
+	if !ok {
+		return zero, fmt.Errorf("%w: %s returned %T instead of %s",
+			ErrUnexpectedResult, q.QueryType(), result, reflect.TypeFor[R]())
+	}
+
+	return typed, nil
+}
@@ -0,0 +1,218 @@
+package querybus_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-faker/faker/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/cqrs"
+	"github.com/screwyprof/cqrs/x/querybus"
+)
+
+var errCannotAnswerQuery = errors.New("cannot answer query")
+
+// ensure that InMemoryQueryBus implements cqrs.QueryHandler interface.
+var _ cqrs.QueryHandler = (*querybus.InMemoryQueryBus)(nil)
+
+type getGreeting struct {
+	Name string
+}
+
+func (q getGreeting) QueryType() string {
+	return "GetGreeting"
+}
+
+type getGreetingByPointer struct {
+	Name string
+}
+
+func (q *getGreetingByPointer) QueryType() string {
+	return "GetGreetingByPointer"
+}
+
+type getGreetingElsewhere struct{}
+
+func (q getGreetingElsewhere) QueryType() string {
+	return "GetGreeting"
+}
+
+func TestNewInMemoryQueryBus(t *testing.T) {
+	t.Run("ItCreatesNewInstance", func(t *testing.T) {
+		assert.True(t, querybus.NewInMemoryQueryBus() != nil)
+	})
+}
+
+func TestInMemoryQueryBusHandle(t *testing.T) {
+	t.Run("ItRoutesTheQueryToTheRegisteredHandler", func(t *testing.T) {
+		// arrange
+		b := querybus.NewInMemoryQueryBus()
+		querybus.Register(b, greet)
+
+		name := faker.FirstName()
+
+		// act
+		got, err := b.Handle(getGreeting{Name: name})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "Hello, "+name, got)
+	})
+
+	t.Run("ItRoutesAQueryOfPointerType", func(t *testing.T) {
+		// arrange
+		b := querybus.NewInMemoryQueryBus()
+		querybus.Register(b, func(q *getGreetingByPointer) (string, error) {
+			return "Hello, " + q.Name, nil
+		})
+
+		name := faker.FirstName()
+
+		// act
+		got, err := b.Handle(&getGreetingByPointer{Name: name})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "Hello, "+name, got)
+	})
+
+	t.Run("ItFailsIfTheHandlerIsNotFound", func(t *testing.T) {
+		// arrange
+		b := querybus.NewInMemoryQueryBus()
+
+		// act
+		_, err := b.Handle(getGreeting{})
+
+		// assert
+		assert.ErrorIs(t, err, querybus.ErrQueryHandlerNotFound)
+	})
+
+	t.Run("ItFailsIfTheHandlerFails", func(t *testing.T) {
+		// arrange
+		b := querybus.NewInMemoryQueryBus()
+		b.RegisterHandler("GetGreeting", func(cqrs.Query) (any, error) {
+			return nil, errCannotAnswerQuery
+		})
+
+		// act
+		_, err := b.Handle(getGreeting{})
+
+		// assert
+		assert.ErrorIs(t, err, errCannotAnswerQuery)
+	})
+
+	t.Run("ItFailsIfAQueryOfAnotherTypeSharesTheQueryType", func(t *testing.T) {
+		// arrange
+		b := querybus.NewInMemoryQueryBus()
+		querybus.Register(b, greet)
+
+		// act
+		_, err := b.Handle(getGreetingElsewhere{})
+
+		// assert
+		assert.ErrorIs(t, err, querybus.ErrUnexpectedQuery)
+	})
+
+	t.Run("ItWrapsTheHandlersWithTheMiddlewareInOrder", func(t *testing.T) {
+		// arrange
+		var calls []string
+
+		trace := func(name string) querybus.Middleware {
+			return func(next cqrs.QueryHandlerFunc) cqrs.QueryHandlerFunc {
+				return func(q cqrs.Query) (any, error) {
+					calls = append(calls, name+" before")
+					result, err := next(q)
+					calls = append(calls, name+" after")
+
+					return result, err
+				}
+			}
+		}
+
+		b := querybus.NewInMemoryQueryBus(querybus.WithMiddleware(trace("outer"), trace("inner")))
+		querybus.Register(b, func(q getGreeting) (string, error) {
+			calls = append(calls, "handler")
+
+			return greet(q)
+		})
+
+		// act
+		_, err := b.Handle(getGreeting{})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"outer before", "inner before", "handler", "inner after", "outer after"}, calls)
+	})
+}
+
+func TestInMemoryQueryBusMiddleware(t *testing.T) {
+	t.Run("ItWrapsTheHandlerOnceWhenItIsRegistered", func(t *testing.T) {
+		// arrange
+		wrapped := 0
+
+		b := querybus.NewInMemoryQueryBus(querybus.WithMiddleware(func(next cqrs.QueryHandlerFunc) cqrs.QueryHandlerFunc {
+			wrapped++
+
+			return next
+		}))
+		querybus.Register(b, greet)
+
+		// act
+		for range 3 {
+			_, err := b.Handle(getGreeting{})
+			assert.NoError(t, err)
+		}
+
+		// assert
+		assert.Equal(t, 1, wrapped)
+	})
+}
+
+func TestAsk(t *testing.T) {
+	t.Run("ItReturnsTheTypedResult", func(t *testing.T) {
+		// arrange
+		b := querybus.NewInMemoryQueryBus()
+		querybus.Register(b, greet)
+
+		name := faker.FirstName()
+
+		// act
+		got, err := querybus.Ask[string](b, getGreeting{Name: name})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "Hello, "+name, got)
+	})
+
+	t.Run("ItFailsIfTheResultIsOfAnotherType", func(t *testing.T) {
+		// arrange
+		b := querybus.NewInMemoryQueryBus()
+		querybus.Register(b, greet)
+
+		// act
+		_, err := querybus.Ask[int](b, getGreeting{})
+
+		// assert
+		assert.ErrorIs(t, err, querybus.ErrUnexpectedResult)
+	})
+
+	t.Run("ItReturnsTheZeroValueForANilResult", func(t *testing.T) {
+		// arrange
+		b := querybus.NewInMemoryQueryBus()
+		querybus.Register(b, func(getGreeting) (*string, error) {
+			return nil, nil
+		})
+
+		// act
+		got, err := querybus.Ask[*string](b, getGreeting{})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+	})
+}
+
+func greet(q getGreeting) (string, error) {
+	return "Hello, " + q.Name, nil
+}
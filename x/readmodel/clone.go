@@ -0,0 +1,86 @@
+package readmodel
+
+import "reflect"
+
+// deepCopy returns a copy of the value which shares no pointers, slices or maps with the original.
+//
+// Unexported struct fields and the values behind interfaces are copied shallowly.
+// A pointer is copied once, so the copy keeps the cycles and the shared pointers of the original.
+func deepCopy[V any](value V) V {
+	c := copier{copied: make(map[pointer]reflect.Value)}
+	copied, _ := c.copyValue(reflect.ValueOf(&value).Elem()).Interface().(V)
+
+	return copied
+}
+
+// pointer identifies a pointer which has been copied.
+type pointer struct {
+	address uintptr
+	typ     reflect.Type
+}
+
+type copier struct {
+	copied map[pointer]reflect.Value
+}
+
+func (c copier) copyValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+
+		p := pointer{address: v.Pointer(), typ: v.Type()}
+		if copied, ok := c.copied[p]; ok {
+			return copied
+		}
+
+		copied := reflect.New(v.Type().Elem())
+		c.copied[p] = copied
+		copied.Elem().Set(c.copyValue(v.Elem()))
+
+		return copied
+	case reflect.Struct:
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(v)
+
+		for i := 0; i < v.NumField(); i++ {
+			if field := copied.Field(i); field.CanSet() {
+				field.Set(c.copyValue(v.Field(i)))
+			}
+		}
+
+		return copied
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+
+		copied := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			copied.Index(i).Set(c.copyValue(v.Index(i)))
+		}
+
+		return copied
+	case reflect.Array:
+		copied := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			copied.Index(i).Set(c.copyValue(v.Index(i)))
+		}
+
+		return copied
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+
+		copied := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			copied.SetMapIndex(iter.Key(), c.copyValue(iter.Value()))
+		}
+
+		return copied
+	default:
+		return v
+	}
+}
@@ -0,0 +1,94 @@
+package readmodel
+
+import (
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Query selects the read models returned by Store.Find.
+//
+// All the fields are optional, the zero Query returns all the read models in the order of their keys.
+type Query[V any] struct {
+	// Index and IndexValue select the read models indexed under the value, see WithIndex.
+	Index      string
+	IndexValue string
+
+	// Filter selects the read models it returns true for.
+	Filter func(V) bool
+
+	// Less sorts the read models, the ties are broken by the keys.
+	Less func(a, b V) bool
+
+	// Limit is the maximum number of the read models in a page, all of them are returned if it is not positive.
+	Limit int
+
+	// After is the cursor of the page to return, it is taken from Page.Next.
+	//
+	// The cursor holds the key of the last read model of the previous page encoded with encoding/json,
+	// so the keys must survive a JSON round trip, which all the keys of integer, float and string kinds do.
+	After string
+}
+
+// Page is a page of the read models found by Store.Find.
+type Page[V any] struct {
+	Items []V
+
+	// Next is the cursor of the next page, it is empty if this is the last page.
+	Next string
+}
+
+// cursor is the position a page ends at.
+type cursor[K comparable] struct {
+	// Key is the key of the last read model of the page.
+	Key K `json:"k"`
+
+	// End is the number of the read models up to the end of the page.
+	End int `json:"e"`
+}
+
+// encodeCursor encodes the cursor, the key is encoded with encoding/json.
+func encodeCursor[K comparable](key K, end int) (string, error) {
+	data, err := json.Marshal(cursor[K]{Key: key, End: end})
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeCursor[K comparable](encoded string) (cursor[K], error) {
+	var c cursor[K]
+
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return c, fmt.Errorf("%w: %s", ErrInvalidCursor, encoded)
+	}
+
+	if err := json.Unmarshal(data, &c); err != nil {
+		return c, fmt.Errorf("%w: %s", ErrInvalidCursor, encoded)
+	}
+
+	return c, nil
+}
+
+// compareKeys orders the keys of integer, float and string kinds by their values
+// and the keys of other kinds by their fmt.Sprint form.
+func compareKeys[K comparable](a, b K) int {
+	x, y := reflect.ValueOf(a), reflect.ValueOf(b)
+
+	switch x.Kind() { //nolint:exhaustive
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cmp.Compare(x.Int(), y.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return cmp.Compare(x.Uint(), y.Uint())
+	case reflect.Float32, reflect.Float64:
+		return cmp.Compare(x.Float(), y.Float())
+	case reflect.String:
+		return cmp.Compare(x.String(), y.String())
+	default:
+		return cmp.Compare(fmt.Sprint(a), fmt.Sprint(b))
+	}
+}
@@ -0,0 +1,356 @@
+// Package readmodel provides a generic in-memory store for read models.
+package readmodel
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+var (
+	// ErrNotFound is returned when there is no read model with the given key.
+	ErrNotFound = errors.New("read model is not found")
+
+	// ErrIndexNotFound is returned when a query uses an index which has not been defined.
+	ErrIndexNotFound = errors.New("index is not found")
+
+	// ErrInvalidCursor is returned when a query uses a cursor which has not been returned by Store.Find.
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// Store keeps read models of type V by keys of type K in memory.
+//
+// The read models are copied on the way in and out, so the callers never share them with the store.
+// It is safe for concurrent use.
+type Store[K comparable, V any] struct {
+	items   map[K]V
+	indexes map[string]*index[K, V]
+	itemsMu sync.RWMutex
+
+	subscriptions map[*subscription[K]]struct{}
+
+	clone       func(V) V
+	compareKeys func(a, b K) int
+}
+
+// Option configures Store.
+type Option[K comparable, V any] func(*Store[K, V])
+
+// WithIndex defines a secondary index which groups the read models by the value returned by indexValue.
+func WithIndex[K comparable, V any](name string, indexValue func(V) string) Option[K, V] {
+	return func(s *Store[K, V]) {
+		s.indexes[name] = &index[K, V]{value: indexValue, keys: make(map[string]map[K]struct{})}
+	}
+}
+
+// WithClone sets the function the read models are copied with.
+//
+// By default the read models are deep copied with reflection.
+func WithClone[K comparable, V any](clone func(V) V) Option[K, V] {
+	return func(s *Store[K, V]) {
+		s.clone = clone
+	}
+}
+
+// WithKeyOrder sets the function the keys are ordered with, it returns a negative number if a goes before b,
+// a positive number if a goes after b and zero if the keys are equal.
+//
+// By default the keys of integer, float and string kinds are ordered by their values
+// and the keys of other kinds by their fmt.Sprint form, set the order for such keys
+// if distinct keys may share the form.
+func WithKeyOrder[K comparable, V any](compare func(a, b K) int) Option[K, V] {
+	return func(s *Store[K, V]) {
+		s.compareKeys = compare
+	}
+}
+
+// New creates a new instance of Store.
+func New[K comparable, V any](opts ...Option[K, V]) *Store[K, V] {
+	s := &Store[K, V]{
+		items:   make(map[K]V),
+		indexes: make(map[string]*index[K, V]),
+		clone:   deepCopy[V],
+
+		compareKeys: compareKeys[K],
+
+		subscriptions: make(map[*subscription[K]]struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Get returns a copy of the read model with the given key.
+func (s *Store[K, V]) Get(key K) (V, error) {
+	s.itemsMu.RLock()
+	defer s.itemsMu.RUnlock()
+
+	item, ok := s.items[key]
+	if !ok {
+		var zero V
+
+		return zero, fmt.Errorf("%w: %v", ErrNotFound, key)
+	}
+
+	return s.clone(item), nil
+}
+
+// Put stores a copy of the read model under the given key replacing the previous one.
+func (s *Store[K, V]) Put(key K, value V) {
+	value = s.clone(value)
+
+	_ = s.write(key, func() error {
+		s.put(key, value)
+
+		return nil
+	})
+}
+
+// Delete removes the read model with the given key if there is one.
+func (s *Store[K, V]) Delete(key K) {
+	// there is nothing to notify about if there is no read model with the key
+	_ = s.write(key, func() error {
+		if _, ok := s.items[key]; !ok {
+			return ErrNotFound
+		}
+
+		s.unindex(key)
+		delete(s.items, key)
+
+		return nil
+	})
+}
+
+// Update modifies the read model with the given key in place.
+//
+// The function is given a copy of the read model which replaces the stored one unless the function fails.
+// No other writes to the store happen while the function runs.
+func (s *Store[K, V]) Update(key K, update func(*V) error) error {
+	return s.write(key, func() error {
+		item, ok := s.items[key]
+		if !ok {
+			return fmt.Errorf("%w: %v", ErrNotFound, key)
+		}
+
+		updated := s.clone(item)
+		if err := update(&updated); err != nil {
+			return err
+		}
+
+		s.put(key, updated)
+
+		return nil
+	})
+}
+
+// write applies the change of the read model with the given key with the write lock held,
+// then notifies the live queries it may affect once the lock is released.
+func (s *Store[K, V]) write(key K, change func() error) error {
+	subs, err := s.change(key, change)
+	if err != nil {
+		return err
+	}
+
+	notify(subs)
+
+	return nil
+}
+
+func (s *Store[K, V]) change(key K, change func() error) ([]*subscription[K], error) {
+	s.itemsMu.Lock()
+	defer s.itemsMu.Unlock()
+
+	if err := change(); err != nil {
+		return nil, err
+	}
+
+	return s.affected(key), nil
+}
+
+// Find returns a page of copies of the read models selected by the query.
+func (s *Store[K, V]) Find(q Query[V]) (Page[V], error) {
+	page, err := s.findLocked(q)
+	if err != nil {
+		return Page[V]{}, err
+	}
+
+	return s.clonePage(page), nil
+}
+
+func (s *Store[K, V]) findLocked(q Query[V]) (Page[V], error) {
+	s.itemsMu.RLock()
+	defer s.itemsMu.RUnlock()
+
+	return s.find(q)
+}
+
+// clonePage copies the read models of the page, the stored read models are replaced
+// rather than modified, so they are safe to copy without the lock held.
+func (s *Store[K, V]) clonePage(page Page[V]) Page[V] {
+	items := make([]V, 0, len(page.Items))
+	for _, item := range page.Items {
+		items = append(items, s.clone(item))
+	}
+
+	page.Items = items
+
+	return page
+}
+
+// find returns a page of the stored read models selected by the query, it must be called with the lock held.
+
+func (s *Store[K, V]) find(q Query[V]) (Page[V], error) {
+	keys, err := s.candidates(q)
+	if err != nil {
+		return Page[V]{}, err
+	}
+
+	keys = slices.DeleteFunc(keys, func(key K) bool {
+		return q.Filter != nil && !q.Filter(s.items[key])
+	})
+
+	slices.SortFunc(keys, func(a, b K) int {
+		return s.compare(q, a, b)
+	})
+
+	start, err := s.startAfter(keys, q)
+	if err != nil {
+		return Page[V]{}, err
+	}
+
+	end := len(keys)
+	if q.Limit > 0 {
+		end = min(start+q.Limit, len(keys))
+	}
+
+	page := Page[V]{Items: make([]V, 0, end-start)}
+	for _, key := range keys[start:end] {
+		page.Items = append(page.Items, s.items[key])
+	}
+
+	if end < len(keys) {
+		page.Next, err = encodeCursor(keys[end-1], end)
+		if err != nil {
+			return Page[V]{}, err
+		}
+	}
+
+	return page, nil
+}
+
+// compare orders the read models with the given keys as the query sorts them.
+func (s *Store[K, V]) compare(q Query[V], a, b K) int {
+	if q.Less != nil {
+		if q.Less(s.items[a], s.items[b]) {
+			return -1
+		}
+
+		if q.Less(s.items[b], s.items[a]) {
+			return 1
+		}
+	}
+
+	return s.compareKeys(a, b)
+}
+
+// startAfter returns the index of the first of the sorted keys which goes after the cursor.
+//
+// The read model the cursor points to may have been removed or filtered out since the page was returned.
+// The read models sorted by their keys resume right after the cursor key anyway, while the read models
+// sorted by their values resume at the position the page ended, as the value of a removed read model is unknown.
+func (s *Store[K, V]) startAfter(keys []K, q Query[V]) (int, error) {
+	if q.After == "" {
+		return 0, nil
+	}
+
+	after, err := decodeCursor[K](q.After)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, ok := s.items[after.Key]; !ok && q.Less != nil {
+		return min(max(after.End-1, 0), len(keys)), nil
+	}
+
+	start, _ := slices.BinarySearchFunc(keys, after.Key, func(key, target K) int {
+		if s.compare(q, key, target) <= 0 {
+			return -1
+		}
+
+		return 1
+	})
+
+	return start, nil
+}
+
+func (s *Store[K, V]) candidates(q Query[V]) ([]K, error) {
+	if q.Index == "" {
+		keys := make([]K, 0, len(s.items))
+		for key := range s.items {
+			keys = append(keys, key)
+		}
+
+		return keys, nil
+	}
+
+	idx, ok := s.indexes[q.Index]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrIndexNotFound, q.Index)
+	}
+
+	keys := make([]K, 0, len(idx.keys[q.IndexValue]))
+	for key := range idx.keys[q.IndexValue] {
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func (s *Store[K, V]) put(key K, value V) {
+	s.unindex(key)
+	s.items[key] = value
+
+	for _, idx := range s.indexes {
+		idx.add(key, value)
+	}
+}
+
+func (s *Store[K, V]) unindex(key K) {
+	item, ok := s.items[key]
+	if !ok {
+		return
+	}
+
+	for _, idx := range s.indexes {
+		idx.remove(key, item)
+	}
+}
+
+// index maps the index values to the keys of the read models.
+type index[K comparable, V any] struct {
+	value func(V) string
+	keys  map[string]map[K]struct{}
+}
+
+func (idx *index[K, V]) add(key K, item V) {
+	value := idx.value(item)
+	if _, ok := idx.keys[value]; !ok {
+		idx.keys[value] = make(map[K]struct{})
+	}
+
+	idx.keys[value][key] = struct{}{}
+}
+
+func (idx *index[K, V]) remove(key K, item V) {
+	value := idx.value(item)
+
+	delete(idx.keys[value], key)
+
+	if len(idx.keys[value]) == 0 {
+		delete(idx.keys, value)
+	}
+}
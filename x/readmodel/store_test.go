@@ -0,0 +1,413 @@
+package readmodel_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/cqrs/x/readmodel"
+)
+
+var errCannotUpdate = errors.New("cannot update")
+
+type ledger struct {
+	Amount int64
+}
+
+type node struct {
+	Name string
+	Next *node
+}
+
+type account struct {
+	Number  string
+	Owner   string
+	Balance int64
+	Ledgers []ledger
+	Tags    map[string]string
+}
+
+func TestStoreGet(t *testing.T) {
+	t.Run("ItReturnsTheStoredReadModel", func(t *testing.T) {
+		// arrange
+		s := readmodel.New[string, *account]()
+		want := &account{Number: "ACC777", Ledgers: []ledger{{Amount: 100}}}
+		s.Put("1", want)
+
+		// act
+		got, err := s.Get("1")
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("ItFailsIfTheReadModelIsNotFound", func(t *testing.T) {
+		// arrange
+		s := readmodel.New[string, *account]()
+
+		// act
+		_, err := s.Get("1")
+
+		// assert
+		assert.ErrorIs(t, err, readmodel.ErrNotFound)
+	})
+
+	t.Run("ItReturnsACopyOfTheReadModel", func(t *testing.T) {
+		// arrange
+		s := readmodel.New[string, *account]()
+		s.Put("1", &account{Ledgers: []ledger{{Amount: 100}}, Tags: map[string]string{"kind": "savings"}})
+
+		// act
+		got, _ := s.Get("1")
+		got.Ledgers[0].Amount = 200
+		got.Tags["kind"] = "checking"
+
+		// assert
+		stored, _ := s.Get("1")
+		assert.Equal(t, int64(100), stored.Ledgers[0].Amount)
+		assert.Equal(t, "savings", stored.Tags["kind"])
+	})
+
+	t.Run("ItCopiesACyclicReadModel", func(t *testing.T) {
+		// arrange
+		s := readmodel.New[string, *node]()
+
+		first := &node{Name: "first"}
+		first.Next = &node{Name: "second", Next: first}
+		s.Put("1", first)
+
+		// act
+		got, err := s.Get("1")
+
+		// assert
+		assert.NoError(t, err)
+		assert.NotSame(t, first, got)
+		assert.Equal(t, "second", got.Next.Name)
+		assert.Same(t, got, got.Next.Next)
+	})
+}
+
+func TestStorePut(t *testing.T) {
+	t.Run("ItStoresACopyOfTheReadModel", func(t *testing.T) {
+		// arrange
+		s := readmodel.New[string, *account]()
+		acc := &account{Ledgers: []ledger{{Amount: 100}}}
+
+		// act
+		s.Put("1", acc)
+		acc.Ledgers[0].Amount = 200
+
+		// assert
+		stored, _ := s.Get("1")
+		assert.Equal(t, int64(100), stored.Ledgers[0].Amount)
+	})
+
+	t.Run("ItCopiesTheReadModelWithTheGivenFunction", func(t *testing.T) {
+		// arrange
+		var cloned int
+
+		s := readmodel.New(readmodel.WithClone[string](func(acc account) account {
+			cloned++
+
+			return acc
+		}))
+
+		// act
+		s.Put("1", account{})
+
+		// assert
+		assert.Equal(t, 1, cloned)
+	})
+}
+
+func TestStoreDelete(t *testing.T) {
+	t.Run("ItRemovesTheReadModelAndItsIndexEntries", func(t *testing.T) {
+		// arrange
+		s := readmodel.New(byOwner())
+		s.Put("1", &account{Owner: "alice"})
+
+		// act
+		s.Delete("1")
+
+		// assert
+		_, err := s.Get("1")
+		assert.ErrorIs(t, err, readmodel.ErrNotFound)
+
+		page, err := s.Find(readmodel.Query[*account]{Index: "owner", IndexValue: "alice"})
+		assert.NoError(t, err)
+		assert.Empty(t, page.Items)
+	})
+}
+
+func TestStoreUpdate(t *testing.T) {
+	t.Run("ItModifiesTheReadModelInPlace", func(t *testing.T) {
+		// arrange
+		s := readmodel.New[string, *account]()
+		s.Put("1", &account{Balance: 100})
+
+		// act
+		err := s.Update("1", func(acc **account) error {
+			(*acc).Balance += 50
+
+			return nil
+		})
+
+		// assert
+		assert.NoError(t, err)
+
+		got, _ := s.Get("1")
+		assert.Equal(t, int64(150), got.Balance)
+	})
+
+	t.Run("ItKeepsTheReadModelIfTheUpdateFails", func(t *testing.T) {
+		// arrange
+		s := readmodel.New[string, account]()
+		s.Put("1", account{Balance: 100})
+
+		// act
+		err := s.Update("1", func(acc *account) error {
+			acc.Balance = 0
+
+			return errCannotUpdate
+		})
+
+		// assert
+		assert.ErrorIs(t, err, errCannotUpdate)
+
+		got, _ := s.Get("1")
+		assert.Equal(t, int64(100), got.Balance)
+	})
+
+	t.Run("ItFailsIfTheReadModelIsNotFound", func(t *testing.T) {
+		// arrange
+		s := readmodel.New[string, account]()
+
+		// act
+		err := s.Update("1", func(*account) error { return nil })
+
+		// assert
+		assert.ErrorIs(t, err, readmodel.ErrNotFound)
+	})
+
+	t.Run("ItReindexesTheReadModel", func(t *testing.T) {
+		// arrange
+		s := readmodel.New(byOwner())
+		s.Put("1", &account{Owner: "alice"})
+
+		// act
+		err := s.Update("1", func(acc **account) error {
+			(*acc).Owner = "bob"
+
+			return nil
+		})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Empty(t, numbers(t, s, readmodel.Query[*account]{Index: "owner", IndexValue: "alice"}))
+	})
+}
+
+func TestStoreFind(t *testing.T) {
+	t.Run("ItReturnsAllTheReadModelsInTheOrderOfTheKeys", func(t *testing.T) {
+		// arrange
+		s := createStore()
+
+		// act
+		got := numbers(t, s, readmodel.Query[*account]{})
+
+		// assert
+		assert.Equal(t, []string{"ACC1", "ACC2", "ACC3", "ACC4"}, got)
+	})
+
+	t.Run("ItOrdersIntegerKeysByTheirValues", func(t *testing.T) {
+		// arrange
+		s := readmodel.New[int, *account]()
+		s.Put(10, &account{Number: "ACC10"})
+		s.Put(9, &account{Number: "ACC9"})
+		s.Put(2, &account{Number: "ACC2"})
+
+		// act
+		page, err := s.Find(readmodel.Query[*account]{})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"ACC2", "ACC9", "ACC10"}, accountNumbers(page.Items))
+	})
+
+	t.Run("ItOrdersTheKeysWithTheGivenOrder", func(t *testing.T) {
+		// arrange
+		s := readmodel.New(readmodel.WithKeyOrder[string, *account](func(a, b string) int {
+			return strings.Compare(b, a)
+		}))
+		s.Put("1", &account{Number: "ACC1"})
+		s.Put("2", &account{Number: "ACC2"})
+
+		// act
+		got := numbers(t, s, readmodel.Query[*account]{})
+
+		// assert
+		assert.Equal(t, []string{"ACC2", "ACC1"}, got)
+	})
+
+	t.Run("ItFindsTheReadModelsByIndex", func(t *testing.T) {
+		// arrange
+		s := createStore()
+
+		// act
+		got := numbers(t, s, readmodel.Query[*account]{Index: "owner", IndexValue: "alice"})
+
+		// assert
+		assert.Equal(t, []string{"ACC1", "ACC3"}, got)
+	})
+
+	t.Run("ItFailsIfTheIndexIsNotFound", func(t *testing.T) {
+		// arrange
+		s := createStore()
+
+		// act
+		_, err := s.Find(readmodel.Query[*account]{Index: "number"})
+
+		// assert
+		assert.ErrorIs(t, err, readmodel.ErrIndexNotFound)
+	})
+
+	t.Run("ItFiltersAndSortsTheReadModels", func(t *testing.T) {
+		// arrange
+		s := createStore()
+
+		// act
+		got := numbers(t, s, readmodel.Query[*account]{
+			Filter: func(acc *account) bool { return acc.Balance > 100 },
+			Less:   func(a, b *account) bool { return a.Balance > b.Balance },
+		})
+
+		// assert
+		assert.Equal(t, []string{"ACC4", "ACC2", "ACC3"}, got)
+	})
+
+	t.Run("ItPaginatesTheReadModelsWithCursors", func(t *testing.T) {
+		// arrange
+		s := createStore()
+		q := readmodel.Query[*account]{Limit: 3}
+
+		// act
+		first, err := s.Find(q)
+		assert.NoError(t, err)
+
+		q.After = first.Next
+		second, err := s.Find(q)
+		assert.NoError(t, err)
+
+		// assert
+		assert.Len(t, first.Items, 3)
+		assert.NotEmpty(t, first.Next)
+
+		assert.Len(t, second.Items, 1)
+		assert.Equal(t, "ACC4", second.Items[0].Number)
+		assert.Empty(t, second.Next)
+	})
+
+	t.Run("ItResumesAfterTheCursorIfTheReadModelIsDeleted", func(t *testing.T) {
+		// arrange
+		s := createStore()
+		q := readmodel.Query[*account]{Limit: 2}
+
+		first, err := s.Find(q)
+		assert.NoError(t, err)
+
+		s.Delete("2")
+
+		// act
+		q.After = first.Next
+		got := numbers(t, s, q)
+
+		// assert
+		assert.Equal(t, []string{"ACC3", "ACC4"}, got)
+	})
+
+	t.Run("ItResumesAfterTheCursorIfTheReadModelIsFilteredOut", func(t *testing.T) {
+		// arrange
+		s := createStore()
+		q := readmodel.Query[*account]{Limit: 2}
+
+		first, err := s.Find(q)
+		assert.NoError(t, err)
+
+		// act
+		q.After = first.Next
+		q.Filter = func(acc *account) bool { return acc.Number != "ACC2" }
+		got := numbers(t, s, q)
+
+		// assert
+		assert.Equal(t, []string{"ACC3", "ACC4"}, got)
+	})
+
+	t.Run("ItResumesAtThePositionOfASortedPageIfTheReadModelIsDeleted", func(t *testing.T) {
+		// arrange
+		s := createStore()
+		q := readmodel.Query[*account]{
+			Limit: 2,
+			Less:  func(a, b *account) bool { return a.Balance > b.Balance },
+		}
+
+		first, err := s.Find(q)
+		assert.NoError(t, err)
+
+		s.Delete("2")
+
+		// act
+		q.After = first.Next
+		got := numbers(t, s, q)
+
+		// assert
+		assert.Equal(t, []string{"ACC3", "ACC1"}, got)
+	})
+
+	t.Run("ItFailsIfTheCursorIsInvalid", func(t *testing.T) {
+		// arrange
+		s := createStore()
+
+		// act
+		_, err := s.Find(readmodel.Query[*account]{After: "not a cursor"})
+
+		// assert
+		assert.ErrorIs(t, err, readmodel.ErrInvalidCursor)
+	})
+}
+
+func createStore() *readmodel.Store[string, *account] {
+	s := readmodel.New(byOwner())
+	s.Put("4", &account{Number: "ACC4", Owner: "carol", Balance: 400})
+	s.Put("2", &account{Number: "ACC2", Owner: "bob", Balance: 200})
+	s.Put("3", &account{Number: "ACC3", Owner: "alice", Balance: 150})
+	s.Put("1", &account{Number: "ACC1", Owner: "alice", Balance: 100})
+
+	return s
+}
+
+func byOwner() readmodel.Option[string, *account] {
+	return readmodel.WithIndex[string]("owner", func(acc *account) string {
+		return strings.ToLower(acc.Owner)
+	})
+}
+
+func numbers(t *testing.T, s *readmodel.Store[string, *account], q readmodel.Query[*account]) []string {
+	t.Helper()
+
+	page, err := s.Find(q)
+	assert.NoError(t, err)
+
+	return accountNumbers(page.Items)
+}
+
+func accountNumbers(accounts []*account) []string {
+	var got []string
+	for _, acc := range accounts {
+		got = append(got, acc.Number)
+	}
+
+	return got
+}
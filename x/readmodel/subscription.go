@@ -0,0 +1,138 @@
+package readmodel
+
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
+// subscription is a live query which is re-evaluated after the changes of the store which may affect it.
+type subscription[K comparable] struct {
+	// key is the key of the only read model the subscription depends on, it is nil if it depends on all of them.
+	key *K
+
+	update func()
+}
+
+func (sub *subscription[K]) affectedBy(key K) bool {
+	return sub.key == nil || *sub.key == key
+}
+
+// Subscribe runs the query and sends its result to the returned channel, then sends the new
+// result each time a change of the store affects it.
+//
+// Only the latest result is kept for a slow receiver, the stale ones are dropped.
+// The channel is closed once the context is done.
+func (s *Store[K, V]) Subscribe(ctx context.Context, q Query[V]) (<-chan Page[V], error) {
+	if _, err := s.findLocked(q); err != nil {
+		return nil, err
+	}
+
+	results := make(chan Page[V], 1)
+
+	subscribe(ctx, s, nil, results, s.clonePage, func() (Page[V], bool) {
+		page, err := s.find(q)
+
+		return page, err == nil
+	})
+
+	return results, nil
+}
+
+// SubscribeTo sends the read model with the given key to the returned channel, then sends it again
+// each time it changes.
+//
+// Nothing is sent while there is no read model with the key.
+// Only the latest read model is kept for a slow receiver, the stale ones are dropped.
+// The channel is closed once the context is done.
+func (s *Store[K, V]) SubscribeTo(ctx context.Context, key K) <-chan V {
+	results := make(chan V, 1)
+
+	subscribe(ctx, s, &key, results, s.clone, func() (V, bool) {
+		item, ok := s.items[key]
+
+		return item, ok
+	})
+
+	return results
+}
+
+// affected returns the live queries the change of the read model with the given key may affect,
+// it must be called with the write lock held.
+func (s *Store[K, V]) affected(key K) []*subscription[K] {
+	var subs []*subscription[K]
+
+	for sub := range s.subscriptions {
+		if sub.affectedBy(key) {
+			subs = append(subs, sub)
+		}
+	}
+
+	return subs
+}
+
+// notify re-evaluates the live queries, it must be called without the lock held.
+func notify[K comparable](subs []*subscription[K]) {
+	for _, sub := range subs {
+		sub.update()
+	}
+}
+
+// subscribe registers a live query and delivers its current result.
+//
+// The query is evaluated with the read lock held, the result is compared with the last one,
+// copied with clone and delivered once the lock is released, as the stored read models are never modified.
+func subscribe[K comparable, V, T any](
+	ctx context.Context, s *Store[K, V], key *K, results chan T, clone func(T) T, evaluate func() (T, bool),
+) {
+	var (
+		last      T
+		delivered bool
+		closed    bool
+		mu        sync.Mutex
+	)
+
+	sub := &subscription[K]{key: key}
+	sub.update = func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		s.itemsMu.RLock()
+		result, ok := evaluate()
+		s.itemsMu.RUnlock()
+
+		if closed || !ok || (delivered && reflect.DeepEqual(result, last)) {
+			return
+		}
+
+		last, delivered = result, true
+
+		// drop the stale result the receiver has not taken yet
+		select {
+		case <-results:
+		default:
+		}
+
+		results <- clone(result)
+	}
+
+	s.itemsMu.Lock()
+	s.subscriptions[sub] = struct{}{}
+	s.itemsMu.Unlock()
+
+	sub.update()
+
+	go func() {
+		<-ctx.Done()
+
+		s.itemsMu.Lock()
+		delete(s.subscriptions, sub)
+		s.itemsMu.Unlock()
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		closed = true
+		close(results)
+	}()
+}
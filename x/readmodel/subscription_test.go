@@ -0,0 +1,198 @@
+package readmodel_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/cqrs/x/readmodel"
+)
+
+func TestStoreSubscribe(t *testing.T) {
+	t.Run("ItSendsTheCurrentResultAndTheResultsAffectedByChanges", func(t *testing.T) {
+		// arrange
+		s := createStore()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		overdrawn := readmodel.Query[*account]{Filter: func(acc *account) bool { return acc.Balance < 0 }}
+
+		// act
+		results, err := s.Subscribe(ctx, overdrawn)
+		assert.NoError(t, err)
+
+		initial := receive(t, results)
+
+		s.Put("5", &account{Number: "ACC5", Balance: -50})
+		changed := receive(t, results)
+
+		// assert
+		assert.Empty(t, initial.Items)
+		assert.Equal(t, []*account{{Number: "ACC5", Balance: -50}}, changed.Items)
+	})
+
+	t.Run("ItSkipsTheChangesWhichDoNotAffectTheResult", func(t *testing.T) {
+		// arrange
+		s := createStore()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		overdrawn := readmodel.Query[*account]{Filter: func(acc *account) bool { return acc.Balance < 0 }}
+
+		results, err := s.Subscribe(ctx, overdrawn)
+		assert.NoError(t, err)
+
+		receive(t, results)
+
+		// act
+		s.Put("5", &account{Number: "ACC5", Balance: 50})
+
+		// assert
+		assert.Empty(t, results)
+	})
+
+	t.Run("ItKeepsOnlyTheLatestResultForASlowReceiver", func(t *testing.T) {
+		// arrange
+		s := readmodel.New[string, *account]()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		results, err := s.Subscribe(ctx, readmodel.Query[*account]{})
+		assert.NoError(t, err)
+
+		// act
+		s.Put("1", &account{Number: "ACC1"})
+		s.Put("2", &account{Number: "ACC2"})
+
+		// assert
+		assert.Len(t, receive(t, results).Items, 2)
+		assert.Empty(t, results)
+	})
+
+	t.Run("ItCopiesTheResultsWithTheGivenFunction", func(t *testing.T) {
+		// arrange
+		var cloned int
+
+		s := readmodel.New(readmodel.WithClone[string](func(acc *account) *account {
+			cloned++
+			c := *acc
+
+			return &c
+		}))
+		s.Put("1", &account{Number: "ACC1"})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		cloned = 0
+
+		// act
+		results, err := s.Subscribe(ctx, readmodel.Query[*account]{})
+		assert.NoError(t, err)
+
+		// assert
+		assert.Len(t, receive(t, results).Items, 1)
+		assert.Equal(t, 1, cloned)
+	})
+
+	t.Run("ItFailsIfTheQueryIsInvalid", func(t *testing.T) {
+		// arrange
+		s := createStore()
+
+		// act
+		_, err := s.Subscribe(context.Background(), readmodel.Query[*account]{Index: "number"})
+
+		// assert
+		assert.ErrorIs(t, err, readmodel.ErrIndexNotFound)
+	})
+
+	t.Run("ItClosesTheChannelOnceTheContextIsDone", func(t *testing.T) {
+		// arrange
+		s := createStore()
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		results, err := s.Subscribe(ctx, readmodel.Query[*account]{})
+		assert.NoError(t, err)
+
+		receive(t, results)
+
+		// act
+		cancel()
+
+		// assert
+		assert.Eventually(t, func() bool {
+			_, ok := <-results
+
+			return !ok
+		}, time.Second, time.Millisecond)
+	})
+}
+
+func TestStoreSubscribeTo(t *testing.T) {
+	t.Run("ItSendsTheReadModelEachTimeItChanges", func(t *testing.T) {
+		// arrange
+		s := readmodel.New[string, *account]()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// act
+		results := s.SubscribeTo(ctx, "1")
+		assert.Empty(t, results)
+
+		s.Put("1", &account{Number: "ACC1", Balance: 100})
+		created := receive(t, results)
+
+		assert.NoError(t, s.Update("1", func(acc **account) error {
+			(*acc).Balance = 150
+
+			return nil
+		}))
+		updated := receive(t, results)
+
+		s.Put("2", &account{Number: "ACC2"})
+
+		// assert
+		assert.Equal(t, int64(100), created.Balance)
+		assert.Equal(t, int64(150), updated.Balance)
+		assert.Empty(t, results)
+	})
+
+	t.Run("ItSendsACopyOfTheReadModel", func(t *testing.T) {
+		// arrange
+		s := readmodel.New[string, *account]()
+		s.Put("1", &account{Number: "ACC1", Ledgers: []ledger{{Amount: 100}}})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// act
+		got := receive(t, s.SubscribeTo(ctx, "1"))
+		got.Ledgers[0].Amount = 200
+
+		// assert
+		stored, _ := s.Get("1")
+		assert.Equal(t, int64(100), stored.Ledgers[0].Amount)
+	})
+}
+
+func receive[T any](t *testing.T, results <-chan T) T {
+	t.Helper()
+
+	select {
+	case result := <-results:
+		return result
+	case <-time.After(time.Second):
+		t.Fatal("no result received")
+
+		var zero T
+
+		return zero
+	}
+}
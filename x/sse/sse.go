@@ -0,0 +1,81 @@
+// Package sse streams the results of live queries to HTTP clients as Server-Sent Events.
+package sse
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// SubscribeFunc subscribes to a live query described by the request.
+//
+// The subscription is expected to end, i.e. the channel to be closed, once the request context is done.
+type SubscribeFunc[T any] func(r *http.Request) (<-chan T, error)
+
+// Handler streams each result received from a live query as a JSON encoded Server-Sent Event.
+type Handler[T any] struct {
+	subscribe SubscribeFunc[T]
+}
+
+// NewHandler creates a new instance of Handler.
+func NewHandler[T any](subscribe SubscribeFunc[T]) *Handler[T] {
+	if subscribe == nil {
+		panic("subscribe is required")
+	}
+
+	return &Handler[T]{subscribe: subscribe}
+}
+
+// ServeHTTP implements http.Handler interface.
+//
+// It responds with 400 Bad Request if the subscription fails, and streams the results
+// until the subscription ends or the client goes away.
+func (h *Handler[T]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+
+		return
+	}
+
+	results, err := h.subscribe(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case result, ok := <-results:
+			if !ok {
+				return
+			}
+
+			if err := writeEvent(w, result); err != nil {
+				return
+			}
+
+			flusher.Flush()
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, result any) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "data: %s\n\n", data)
+
+	return err
+}
@@ -0,0 +1,72 @@
+package sse_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/cqrs/x/sse"
+)
+
+var errInvalidQuery = errors.New("invalid query")
+
+type balance struct {
+	Number  string `json:"number"`
+	Balance int64  `json:"balance"`
+}
+
+func TestNewHandler(t *testing.T) {
+	t.Run("ItPanicsIfSubscribeIsNotGiven", func(t *testing.T) {
+		factory := func() {
+			sse.NewHandler[balance](nil)
+		}
+		assert.Panics(t, factory)
+	})
+}
+
+func TestHandlerServeHTTP(t *testing.T) {
+	t.Run("ItStreamsTheResultsAsEvents", func(t *testing.T) {
+		// arrange
+		results := make(chan balance, 2)
+		results <- balance{Number: "ACC777", Balance: 100}
+		results <- balance{Number: "ACC777", Balance: 150}
+		close(results)
+
+		h := sse.NewHandler(func(*http.Request) (<-chan balance, error) {
+			return results, nil
+		})
+
+		w := httptest.NewRecorder()
+
+		// act
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/balances", nil))
+
+		// assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+		assert.Equal(t,
+			"data: {\"number\":\"ACC777\",\"balance\":100}\n\n"+
+				"data: {\"number\":\"ACC777\",\"balance\":150}\n\n",
+			w.Body.String(),
+		)
+	})
+
+	t.Run("ItRespondsWithBadRequestIfItCannotSubscribe", func(t *testing.T) {
+		// arrange
+		h := sse.NewHandler(func(*http.Request) (<-chan balance, error) {
+			return nil, errInvalidQuery
+		})
+
+		w := httptest.NewRecorder()
+
+		// act
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/balances", nil))
+
+		// assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), errInvalidQuery.Error())
+	})
+}
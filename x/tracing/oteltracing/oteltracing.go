@@ -0,0 +1,127 @@
+// Package oteltracing adapts an OpenTelemetry tracer to tracing.Tracer.
+package oteltracing
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/screwyprof/cqrs/x/tracing"
+)
+
+const traceParentHeader = "traceparent"
+
+// Tracer starts OpenTelemetry spans.
+//
+// The trace parents are propagated in the W3C Trace Context format.
+type Tracer struct {
+	tracer     trace.Tracer
+	propagator propagation.TraceContext
+}
+
+// New creates a new instance of Tracer, e.g. New(otel.Tracer("github.com/screwyprof/cqrs")).
+func New(tracer trace.Tracer) *Tracer {
+	if tracer == nil {
+		panic("tracer is required")
+	}
+
+	return &Tracer{tracer: tracer}
+}
+
+// Start implements tracing.Tracer interface.
+func (t *Tracer) Start(ctx context.Context, name string, attrs ...slog.Attr) (context.Context, tracing.Span) {
+	ctx, span := t.tracer.Start(ctx, name, trace.WithAttributes(attributes(attrs)...))
+
+	return ctx, &Span{span: span, propagator: t.propagator}
+}
+
+// Continue implements tracing.Tracer interface.
+func (t *Tracer) Continue(ctx context.Context, traceParent string) context.Context {
+	if traceParent == "" {
+		return ctx
+	}
+
+	return t.propagator.Extract(ctx, propagation.MapCarrier{traceParentHeader: traceParent})
+}
+
+// Span is an OpenTelemetry span.
+type Span struct {
+	span       trace.Span
+	propagator propagation.TraceContext
+}
+
+// SetAttributes implements tracing.Span interface.
+func (s *Span) SetAttributes(attrs ...slog.Attr) {
+	s.span.SetAttributes(attributes(attrs)...)
+}
+
+// RecordError implements tracing.Span interface.
+//
+// It also marks the span as failed.
+func (s *Span) RecordError(err error) {
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+// End implements tracing.Span interface.
+func (s *Span) End() {
+	s.span.End()
+}
+
+// TraceParent implements tracing.Span interface.
+func (s *Span) TraceParent() string {
+	carrier := propagation.MapCarrier{}
+	s.propagator.Inject(trace.ContextWithSpan(context.Background(), s.span), carrier)
+
+	return carrier.Get(traceParentHeader)
+}
+
+// attributes converts the slog attributes to the OpenTelemetry ones, the groups are flattened with dotted keys.
+func attributes(attrs []slog.Attr) []attribute.KeyValue {
+	converted := make([]attribute.KeyValue, 0, len(attrs))
+	for _, a := range attrs {
+		converted = appendAttribute(converted, "", a)
+	}
+
+	return converted
+}
+
+func appendAttribute(converted []attribute.KeyValue, prefix string, a slog.Attr) []attribute.KeyValue {
+	key := prefix + a.Key
+	value := a.Value.Resolve()
+
+	switch value.Kind() {
+	case slog.KindGroup:
+		for _, member := range value.Group() {
+			converted = appendAttribute(converted, key+".", member)
+		}
+
+		return converted
+	case slog.KindString:
+		return append(converted, attribute.String(key, value.String()))
+	case slog.KindInt64:
+		return append(converted, attribute.Int64(key, value.Int64()))
+	case slog.KindUint64:
+		return append(converted, attribute.Int64(key, int64(value.Uint64()))) //nolint:gosec
+	case slog.KindFloat64:
+		return append(converted, attribute.Float64(key, value.Float64()))
+	case slog.KindBool:
+		return append(converted, attribute.Bool(key, value.Bool()))
+	case slog.KindDuration:
+		return append(converted, attribute.String(key, value.Duration().String()))
+	case slog.KindTime:
+		return append(converted, attribute.String(key, value.Time().Format(time.RFC3339Nano)))
+	default:
+		if strings, ok := value.Any().([]string); ok {
+			return append(converted, attribute.StringSlice(key, strings))
+		}
+
+		return append(converted, attribute.String(key, fmt.Sprint(value.Any())))
+	}
+}
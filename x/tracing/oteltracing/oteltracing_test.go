@@ -0,0 +1,293 @@
+package oteltracing_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/go-faker/faker/v4"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/screwyprof/cqrs"
+	"github.com/screwyprof/cqrs/aggregate"
+	"github.com/screwyprof/cqrs/aggregate/aggtest"
+	"github.com/screwyprof/cqrs/x"
+	"github.com/screwyprof/cqrs/x/aggstore"
+	"github.com/screwyprof/cqrs/x/dispatcher"
+	"github.com/screwyprof/cqrs/x/eventbus"
+	"github.com/screwyprof/cqrs/x/eventstore"
+	"github.com/screwyprof/cqrs/x/outbox"
+	"github.com/screwyprof/cqrs/x/tracing"
+	"github.com/screwyprof/cqrs/x/tracing/oteltracing"
+)
+
+var errSomethingWentWrong = errors.New("something went wrong")
+
+// ensure that Tracer implements tracing.Tracer interface.
+var _ tracing.Tracer = (*oteltracing.Tracer)(nil)
+
+func TestNew(t *testing.T) {
+	t.Run("ItPanicsIfTracerIsNotGiven", func(t *testing.T) {
+		assert.Panics(t, func() {
+			oteltracing.New(nil)
+		})
+	})
+}
+
+func TestTracer(t *testing.T) {
+	t.Run("ItConvertsTheAttributes", func(t *testing.T) {
+		// arrange
+		tracer, exporter := createTracer()
+
+		// act
+		_, span := tracer.Start(context.Background(), "span",
+			slog.String("string", "value"),
+			slog.Int("int", 1),
+			slog.Bool("bool", true),
+			slog.Duration("duration", time.Second),
+			slog.Any("strings", []string{"a", "b"}),
+			slog.Group("group", slog.String("member", "value")),
+		)
+		span.SetAttributes(slog.Float64("float", 1.5))
+		span.End()
+
+		// assert
+		want := []attribute.KeyValue{
+			attribute.String("string", "value"),
+			attribute.Int64("int", 1),
+			attribute.Bool("bool", true),
+			attribute.String("duration", "1s"),
+			attribute.StringSlice("strings", []string{"a", "b"}),
+			attribute.String("group.member", "value"),
+			attribute.Float64("float", 1.5),
+		}
+		assert.Equal(t, want, exporter.GetSpans()[0].Attributes)
+	})
+
+	t.Run("ItRecordsTheErrorAndMarksTheSpanAsFailed", func(t *testing.T) {
+		// arrange
+		tracer, exporter := createTracer()
+
+		// act
+		_, span := tracer.Start(context.Background(), "span")
+		tracing.End(span, errSomethingWentWrong)
+
+		// assert
+		got := exporter.GetSpans()[0]
+		assert.Equal(t, codes.Error, got.Status.Code)
+		assert.Equal(t, errSomethingWentWrong.Error(), got.Status.Description)
+		assert.Len(t, got.Events, 1)
+	})
+
+	t.Run("ItContinuesTheTraceOfTheTraceParent", func(t *testing.T) {
+		// arrange
+		tracer, exporter := createTracer()
+
+		_, parent := tracer.Start(context.Background(), "parent")
+		parent.End()
+
+		// act
+		_, child := tracer.Start(tracer.Continue(context.Background(), parent.TraceParent()), "child")
+		child.End()
+
+		// assert
+		spans := exporter.GetSpans()
+		assert.Equal(t, spans[0].SpanContext.TraceID(), spans[1].SpanContext.TraceID())
+		assert.Equal(t, spans[0].SpanContext.SpanID(), spans[1].Parent.SpanID())
+		assert.True(t, spans[1].Parent.IsRemote())
+	})
+
+	t.Run("ItStartsANewTraceIfTheTraceParentIsMalformed", func(t *testing.T) {
+		// arrange
+		tracer, exporter := createTracer()
+
+		// act
+		_, span := tracer.Start(tracer.Continue(context.Background(), "malformed"), "span")
+		span.End()
+
+		// assert
+		assert.False(t, exporter.GetSpans()[0].Parent.IsValid())
+	})
+}
+
+func TestTracedPipeline(t *testing.T) {
+	t.Run("ItTracesTheCommandEndToEnd", func(t *testing.T) {
+		// arrange
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		tracer, exporter := createTracer()
+
+		bus := eventbus.NewInMemoryEventBus(eventbus.WithTracer(tracer))
+		bus.Register(&eventRecorder{})
+
+		eventStore := eventstore.NewInInMemoryEventStore(bus, eventstore.WithTracer(tracer))
+		d := dispatcher.NewDispatcher(
+			aggstore.NewStore(eventStore, createAggregateFactory()),
+			dispatcher.WithTracer(tracer),
+		)
+
+		// act
+		events, err := d.Handle(aggtest.MakeSomethingHappen{AggID: ID})
+
+		// assert
+		assert.NoError(t, err)
+		assert.NotEmpty(t, events[0].(somethingTraced).TraceParent) //nolint:forcetypeassert
+
+		spans := spansByName(exporter.GetSpans())
+		root := spans["cqrs.dispatch"]
+
+		assert.False(t, root.Parent.IsValid())
+		assert.Contains(t, root.Attributes, attribute.String("command_type", "MakeSomethingHappen"))
+
+		for _, name := range []string{"cqrs.aggregate.load", "cqrs.aggregate.handle", "cqrs.aggregate.store"} {
+			assert.Equal(t, root.SpanContext.TraceID(), spans[name].SpanContext.TraceID(), name)
+			assert.Equal(t, root.SpanContext.SpanID(), spans[name].Parent.SpanID(), name)
+		}
+
+		store := spans["cqrs.aggregate.store"]
+		for _, name := range []string{"cqrs.events.append", "cqrs.event.deliver"} {
+			assert.Equal(t, root.SpanContext.TraceID(), spans[name].SpanContext.TraceID(), name)
+			assert.Equal(t, store.SpanContext.SpanID(), spans[name].Parent.SpanID(), name)
+		}
+
+		assert.Contains(t, spans["cqrs.aggregate.load"].Attributes, attribute.Int64("replayed_events", 0))
+	})
+
+	t.Run("ItContinuesTheTraceAcrossTheOutbox", func(t *testing.T) {
+		// arrange
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		tracer, exporter := createTracer()
+
+		eventStore := eventstore.NewInMemoryEventStoreWithOutbox(eventstore.WithTracer(tracer))
+		d := dispatcher.NewDispatcher(
+			aggstore.NewStore(eventStore, createAggregateFactory()),
+			dispatcher.WithTracer(tracer),
+		)
+
+		bus := eventbus.NewInMemoryEventBus(eventbus.WithTracer(tracer))
+		bus.Register(&eventRecorder{})
+
+		_, err := d.Handle(aggtest.MakeSomethingHappen{AggID: ID})
+		assert.NoError(t, err)
+
+		// act
+		delivered, err := outbox.NewRelay(eventStore, bus).Deliver(t.Context())
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, 1, delivered)
+
+		spans := spansByName(exporter.GetSpans())
+		root := spans["cqrs.dispatch"]
+
+		assert.Equal(t, root.SpanContext.TraceID(), spans["cqrs.event.deliver"].SpanContext.TraceID())
+		assert.Equal(t, spans["cqrs.aggregate.store"].SpanContext.SpanID(), spans["cqrs.event.deliver"].Parent.SpanID())
+	})
+
+	t.Run("ItRecordsTheFailureOfTheCommand", func(t *testing.T) {
+		// arrange
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		tracer, exporter := createTracer()
+
+		bus := eventbus.NewInMemoryEventBus(eventbus.WithTracer(tracer))
+		eventStore := eventstore.NewInInMemoryEventStore(bus, eventstore.WithTracer(tracer))
+		d := dispatcher.NewDispatcher(
+			aggstore.NewStore(eventStore, createAggregateFactory()),
+			dispatcher.WithTracer(tracer),
+		)
+
+		_, err := d.Handle(aggtest.MakeSomethingHappen{AggID: ID})
+		assert.NoError(t, err)
+
+		// act
+		_, err = d.Handle(aggtest.MakeSomethingHappen{AggID: ID})
+
+		// assert
+		assert.ErrorIs(t, err, aggtest.ErrItCanHappenOnceOnly)
+
+		var failed []string
+		for _, span := range exporter.GetSpans() {
+			if span.Status.Code == codes.Error {
+				failed = append(failed, span.Name)
+			}
+		}
+
+		assert.ElementsMatch(t, []string{"cqrs.aggregate.handle", "cqrs.dispatch"}, failed)
+	})
+}
+
+func createTracer() (*oteltracing.Tracer, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	return oteltracing.New(provider.Tracer("github.com/screwyprof/cqrs")), exporter
+}
+
+func spansByName(spans tracetest.SpanStubs) map[string]tracetest.SpanStub {
+	byName := make(map[string]tracetest.SpanStub, len(spans))
+	for _, span := range spans {
+		byName[span.Name] = span
+	}
+
+	return byName
+}
+
+// somethingTraced is an event which carries metadata.
+type somethingTraced struct {
+	x.EventMetadata
+}
+
+func (e somethingTraced) EventType() string {
+	return "SomethingTraced"
+}
+
+// tracedAggregate produces the events which carry metadata.
+type tracedAggregate struct {
+	id       cqrs.Identifier
+	happened bool
+}
+
+func (a *tracedAggregate) AggregateID() cqrs.Identifier {
+	return a.id
+}
+
+func (a *tracedAggregate) AggregateType() string {
+	return aggtest.TestAggregateType
+}
+
+func (a *tracedAggregate) MakeSomethingHappen(_ aggtest.MakeSomethingHappen) ([]cqrs.DomainEvent, error) {
+	if a.happened {
+		return nil, aggtest.ErrItCanHappenOnceOnly
+	}
+
+	return []cqrs.DomainEvent{somethingTraced{}}, nil
+}
+
+func (a *tracedAggregate) OnSomethingTraced(_ somethingTraced) {
+	a.happened = true
+}
+
+func createAggregateFactory() *aggregate.Factory {
+	factory := aggregate.NewFactory()
+	factory.RegisterAggregate(aggtest.TestAggregateType, func(ID cqrs.Identifier) cqrs.ESAggregate {
+		return aggregate.FromAggregate(&tracedAggregate{id: ID})
+	})
+
+	return factory
+}
+
+// eventRecorder handles all the events.
+type eventRecorder struct{}
+
+func (h *eventRecorder) SubscribedTo() cqrs.EventMatcher {
+	return func(cqrs.DomainEvent) bool { return true }
+}
+
+func (h *eventRecorder) Handle(cqrs.DomainEvent) error {
+	return nil
+}
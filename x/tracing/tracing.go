@@ -0,0 +1,85 @@
+// Package tracing provides the tracing abstraction the components report their work with.
+//
+// The command handling API does not carry a context, so the spans of a command are linked
+// through the events instead: the dispatcher records the trace parent of the span which stores
+// the produced events in their x.EventMetadata, and the components which store or deliver
+// the events continue the trace under that span, across the asynchronous boundaries as well.
+//
+// Tracing is disabled unless a Tracer is given, see oteltracing for the OpenTelemetry adapter.
+package tracing
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/screwyprof/cqrs"
+	"github.com/screwyprof/cqrs/x"
+)
+
+// Tracer starts spans.
+type Tracer interface {
+	// Start starts a span which is a child of the span in the given context, if any.
+	Start(ctx context.Context, name string, attrs ...slog.Attr) (context.Context, Span)
+
+	// Continue returns the context which continues the trace the given W3C traceparent belongs to.
+	//
+	// The context is returned as is if the traceparent is empty or malformed.
+	Continue(ctx context.Context, traceParent string) context.Context
+}
+
+// Span is a unit of work of a trace.
+type Span interface {
+	SetAttributes(attrs ...slog.Attr)
+	RecordError(err error)
+	End()
+
+	// TraceParent returns the W3C traceparent the span is propagated with, it is empty if the span is not recorded.
+	TraceParent() string
+}
+
+// Noop returns a tracer which does nothing, the components use it unless a tracer is given.
+func Noop() Tracer {
+	return noopTracer{}
+}
+
+// ContinueFrom returns the context which continues the trace of the first of the events carrying a trace parent.
+func ContinueFrom(ctx context.Context, tracer Tracer, events ...cqrs.DomainEvent) context.Context {
+	for _, e := range events {
+		if carrier, ok := e.(x.MetadataCarrier); ok && carrier.Metadata().TraceParent != "" {
+			return tracer.Continue(ctx, carrier.Metadata().TraceParent)
+		}
+	}
+
+	return ctx
+}
+
+// End records the error, if any, and ends the span.
+func End(span Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	span.End()
+}
+
+type noopTracer struct{}
+
+func (noopTracer) Start(ctx context.Context, _ string, _ ...slog.Attr) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+func (noopTracer) Continue(ctx context.Context, _ string) context.Context {
+	return ctx
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...slog.Attr) {}
+
+func (noopSpan) RecordError(error) {}
+
+func (noopSpan) End() {}
+
+func (noopSpan) TraceParent() string {
+	return ""
+}
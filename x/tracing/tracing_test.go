@@ -0,0 +1,132 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/cqrs/aggregate/aggtest"
+	"github.com/screwyprof/cqrs/x"
+	"github.com/screwyprof/cqrs/x/tracing"
+)
+
+const traceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+var errSomethingWentWrong = errors.New("something went wrong")
+
+func TestNoop(t *testing.T) {
+	t.Run("ItDoesNotTrace", func(t *testing.T) {
+		// arrange
+		tracer := tracing.Noop()
+		ctx := context.Background()
+
+		// act
+		got, span := tracer.Start(ctx, "span", slog.String("key", "value"))
+		tracing.End(span, errSomethingWentWrong)
+
+		// assert
+		assert.Equal(t, ctx, got)
+		assert.Equal(t, ctx, tracer.Continue(ctx, traceParent))
+		assert.Empty(t, span.TraceParent())
+	})
+}
+
+func TestContinueFrom(t *testing.T) {
+	t.Run("ItContinuesTheTraceOfTheFirstEventCarryingATraceParent", func(t *testing.T) {
+		// arrange
+		tracer := &tracerSpy{}
+
+		// act
+		tracing.ContinueFrom(context.Background(), tracer,
+			aggtest.SomethingHappened{},
+			somethingTraced{},
+			somethingTraced{EventMetadata: x.EventMetadata{TraceParent: traceParent}},
+		)
+
+		// assert
+		assert.Equal(t, []string{traceParent}, tracer.continued)
+	})
+
+	t.Run("ItReturnsTheContextAsIsIfNoEventCarriesATraceParent", func(t *testing.T) {
+		// arrange
+		tracer := &tracerSpy{}
+		ctx := context.Background()
+
+		// act
+		got := tracing.ContinueFrom(ctx, tracer, aggtest.SomethingHappened{}, somethingTraced{})
+
+		// assert
+		assert.Equal(t, ctx, got)
+		assert.Empty(t, tracer.continued)
+	})
+}
+
+func TestEnd(t *testing.T) {
+	t.Run("ItRecordsTheErrorAndEndsTheSpan", func(t *testing.T) {
+		// arrange
+		span := &spanSpy{}
+
+		// act
+		tracing.End(span, errSomethingWentWrong)
+
+		// assert
+		assert.Equal(t, errSomethingWentWrong, span.err)
+		assert.True(t, span.ended)
+	})
+
+	t.Run("ItEndsTheSpanWithoutAnError", func(t *testing.T) {
+		// arrange
+		span := &spanSpy{}
+
+		// act
+		tracing.End(span, nil)
+
+		// assert
+		assert.NoError(t, span.err)
+		assert.True(t, span.ended)
+	})
+}
+
+type somethingTraced struct {
+	x.EventMetadata
+}
+
+func (e somethingTraced) EventType() string {
+	return "SomethingTraced"
+}
+
+type tracerSpy struct {
+	continued []string
+}
+
+func (t *tracerSpy) Start(ctx context.Context, _ string, _ ...slog.Attr) (context.Context, tracing.Span) {
+	return ctx, &spanSpy{}
+}
+
+func (t *tracerSpy) Continue(ctx context.Context, traceParent string) context.Context {
+	t.continued = append(t.continued, traceParent)
+
+	return ctx
+}
+
+type spanSpy struct {
+	err   error
+	ended bool
+}
+
+func (s *spanSpy) SetAttributes(...slog.Attr) {}
+
+func (s *spanSpy) RecordError(err error) {
+	s.err = err
+}
+
+func (s *spanSpy) End() {
+	s.ended = true
+}
+
+func (s *spanSpy) TraceParent() string {
+	return ""
+}
@@ -0,0 +1,95 @@
+// Package unitofwork provides a unit of work which commits the changes of several aggregates atomically.
+package unitofwork
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/screwyprof/cqrs"
+	"github.com/screwyprof/cqrs/x"
+)
+
+// ErrChangesNotTracked is returned when a loaded aggregate does not keep track of its uncommitted changes.
+var ErrChangesNotTracked = errors.New("aggregate does not track its changes")
+
+// UnitOfWork loads aggregates, keeps track of their pending events and commits them at once.
+//
+// The changes of all the loaded aggregates are stored with x.MultiAggregateStore.StoreMany,
+// so either all of them are stored and published or none of them is.
+// The aggregates must implement x.ChangeTracker, e.g. aggregate.EventSourced does.
+//
+// It is not safe for concurrent use, create a new unit of work per business transaction.
+type UnitOfWork struct {
+	aggregateStore x.MultiAggregateStore
+
+	aggregates map[aggregateKey]cqrs.ESAggregate
+	loaded     []cqrs.ESAggregate
+}
+
+// aggregateKey identifies a loaded aggregate, the identifiers are unique per aggregate type only.
+type aggregateKey struct {
+	aggregateType string
+	aggregateID   string
+}
+
+// New creates a new instance of UnitOfWork.
+//
+// The aggregate store must be able to store several aggregates atomically, e.g. aggstore.AggregateStore
+// backed by an x.MultiStreamEventStore.
+func New(aggregateStore x.MultiAggregateStore) *UnitOfWork {
+	if aggregateStore == nil {
+		panic("aggregateStore is required")
+	}
+
+	return &UnitOfWork{
+		aggregateStore: aggregateStore,
+		aggregates:     make(map[aggregateKey]cqrs.ESAggregate),
+	}
+}
+
+// Load loads the aggregate and starts tracking its changes.
+//
+// An aggregate already loaded by this unit of work is returned as is.
+func (u *UnitOfWork) Load(aggregateID cqrs.Identifier, aggregateType string) (cqrs.ESAggregate, error) {
+	key := aggregateKey{aggregateType: aggregateType, aggregateID: aggregateID.String()}
+
+	if agg, ok := u.aggregates[key]; ok {
+		return agg, nil
+	}
+
+	agg, err := u.aggregateStore.Load(aggregateID, aggregateType)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := agg.(x.ChangeTracker); !ok {
+		return nil, fmt.Errorf("%w: %s %s", ErrChangesNotTracked, aggregateType, aggregateID)
+	}
+
+	u.aggregates[key] = agg
+	u.loaded = append(u.loaded, agg)
+
+	return agg, nil
+}
+
+// Handle loads the aggregate the command is addressed to and lets it handle the command.
+//
+// The produced events are kept pending until the unit of work is committed.
+// It implements cqrs.CommandHandler interface.
+func (u *UnitOfWork) Handle(c cqrs.Command) ([]cqrs.DomainEvent, error) {
+	agg, err := u.Load(c.AggregateID(), c.AggregateType())
+	if err != nil {
+		return nil, err
+	}
+
+	return agg.Handle(c)
+}
+
+// Commit stores the pending events of all the loaded aggregates at once.
+//
+// On success the changes are marked as committed, otherwise nothing is stored and
+// the aggregates keep their pending events. The changes are marked as committed as well
+// if the events are stored but cannot be published, see x.PublishError.
+func (u *UnitOfWork) Commit() error {
+	return u.aggregateStore.StoreMany(u.loaded...)
+}
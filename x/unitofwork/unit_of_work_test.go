@@ -0,0 +1,259 @@
+package unitofwork_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-faker/faker/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/screwyprof/cqrs"
+	"github.com/screwyprof/cqrs/aggregate"
+	"github.com/screwyprof/cqrs/aggregate/aggtest"
+	"github.com/screwyprof/cqrs/x"
+	"github.com/screwyprof/cqrs/x/aggstore"
+	"github.com/screwyprof/cqrs/x/aggstore/aggstoretest"
+	"github.com/screwyprof/cqrs/x/eventbus/evnbustest"
+	"github.com/screwyprof/cqrs/x/eventstore"
+	"github.com/screwyprof/cqrs/x/unitofwork"
+)
+
+const otherAggregateType = "mock.OtherAggregate"
+
+var errCannotPublishEvents = errors.New("cannot publish events")
+
+// ensure that UnitOfWork implements cqrs.CommandHandler interface.
+var _ cqrs.CommandHandler = (*unitofwork.UnitOfWork)(nil)
+
+func TestNew(t *testing.T) {
+	t.Run("ItPanicsIfAggregateStoreIsNotGiven", func(t *testing.T) {
+		factory := func() {
+			unitofwork.New(nil)
+		}
+		assert.Panics(t, factory)
+	})
+}
+
+func TestUnitOfWorkLoad(t *testing.T) {
+	t.Run("ItReturnsTheAlreadyLoadedAggregate", func(t *testing.T) {
+		// arrange
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		uow, _, _ := createUnitOfWork()
+
+		// act
+		first, err := uow.Load(ID, aggtest.TestAggregateType)
+		assert.NoError(t, err)
+
+		second, err := uow.Load(ID, aggtest.TestAggregateType)
+		assert.NoError(t, err)
+
+		// assert
+		assert.Same(t, first, second)
+	})
+
+	t.Run("ItTellsApartTheAggregatesOfDifferentTypesSharingTheIdentifier", func(t *testing.T) {
+		// arrange
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		uow, _, _ := createUnitOfWork()
+
+		// act
+		first, err := uow.Load(ID, aggtest.TestAggregateType)
+		assert.NoError(t, err)
+
+		second, err := uow.Load(ID, otherAggregateType)
+		assert.NoError(t, err)
+
+		// assert
+		assert.NotSame(t, first, second)
+	})
+
+	t.Run("ItFailsIfItCannotLoadAggregate", func(t *testing.T) {
+		// arrange
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		uow := unitofwork.New(createAggregateStoreMock(nil, aggstoretest.ErrAggregateStoreCannotLoadAggregate))
+
+		// act
+		_, err := uow.Handle(aggtest.MakeSomethingHappen{AggID: ID})
+
+		// assert
+		assert.ErrorIs(t, err, aggstoretest.ErrAggregateStoreCannotLoadAggregate)
+	})
+
+	t.Run("ItFailsIfAggregateDoesNotTrackItsChanges", func(t *testing.T) {
+		// arrange
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		untracked := struct{ cqrs.ESAggregate }{aggregate.FromAggregate(aggtest.NewTestAggregate(ID))}
+
+		uow := unitofwork.New(createAggregateStoreMock(untracked, nil))
+
+		// act
+		_, err := uow.Load(ID, aggtest.TestAggregateType)
+
+		// assert
+		assert.ErrorIs(t, err, unitofwork.ErrChangesNotTracked)
+	})
+}
+
+func TestUnitOfWorkCommit(t *testing.T) {
+	t.Run("ItCommitsTheChangesOfAllTheAggregates", func(t *testing.T) {
+		// arrange
+		firstID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		secondID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+
+		uow, es, published := createUnitOfWork()
+
+		_, err := uow.Handle(aggtest.MakeSomethingHappen{AggID: firstID})
+		assert.NoError(t, err)
+
+		_, err = uow.Handle(aggtest.MakeSomethingHappen{AggID: secondID})
+		assert.NoError(t, err)
+
+		assert.Empty(t, *published)
+
+		// act
+		err = uow.Commit()
+
+		// assert
+		assert.NoError(t, err)
+		assertStream(t, es, firstID, aggtest.SomethingHappened{})
+		assertStream(t, es, secondID, aggtest.SomethingHappened{})
+		assert.Equal(t, []cqrs.DomainEvent{aggtest.SomethingHappened{}, aggtest.SomethingHappened{}}, *published)
+	})
+
+	t.Run("ItCommitsNothingIfAnyAggregateHasBeenModifiedConcurrently", func(t *testing.T) {
+		// arrange
+		firstID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		secondID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+
+		uow, es, published := createUnitOfWork()
+
+		_, err := uow.Handle(aggtest.MakeSomethingHappen{AggID: firstID})
+		assert.NoError(t, err)
+
+		_, err = uow.Handle(aggtest.MakeSomethingHappen{AggID: secondID})
+		assert.NoError(t, err)
+
+		concurrent := aggtest.SomethingElseHappened{}
+		assert.NoError(t, es.StoreEventsFor(secondID, 0, []cqrs.DomainEvent{concurrent}))
+
+		// act
+		err = uow.Commit()
+
+		// assert
+		assert.ErrorIs(t, err, eventstore.ErrConcurrencyViolation)
+		assertStream(t, es, firstID)
+		assertStream(t, es, secondID, concurrent)
+		assert.Equal(t, []cqrs.DomainEvent{concurrent}, *published)
+	})
+
+	t.Run("ItMarksTheChangesAsCommitted", func(t *testing.T) {
+		// arrange
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		uow, _, _ := createUnitOfWork()
+
+		agg, err := uow.Load(ID, aggtest.TestAggregateType)
+		assert.NoError(t, err)
+
+		_, err = agg.Handle(aggtest.MakeSomethingHappen{AggID: ID})
+		assert.NoError(t, err)
+
+		// act
+		err = uow.Commit()
+
+		// assert
+		assert.NoError(t, err)
+		assert.Empty(t, agg.(*aggregate.EventSourced).Changes()) //nolint:forcetypeassert
+		assert.Equal(t, 1, agg.Version())
+	})
+
+	t.Run("ItMarksTheChangesAsCommittedIfTheEventsCannotBePublished", func(t *testing.T) {
+		// arrange
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+
+		es := eventstore.NewInInMemoryEventStore(createEventPublisherMock(errCannotPublishEvents))
+		uow := unitofwork.New(aggstore.NewStore(es, createAggregateFactory()))
+
+		_, err := uow.Handle(aggtest.MakeSomethingHappen{AggID: ID})
+		assert.NoError(t, err)
+
+		// act
+		err = uow.Commit()
+
+		// assert
+		var publishErr *x.PublishError
+		assert.ErrorAs(t, err, &publishErr)
+		assert.NoError(t, uow.Commit())
+		assertStream(t, es, ID, aggtest.SomethingHappened{})
+	})
+
+	t.Run("ItDoesNothingIfThereAreNoChanges", func(t *testing.T) {
+		// arrange
+		ID := aggtest.StringIdentifier(faker.UUIDHyphenated())
+		uow, _, published := createUnitOfWork()
+
+		_, err := uow.Load(ID, aggtest.TestAggregateType)
+		assert.NoError(t, err)
+
+		// act
+		err = uow.Commit()
+
+		// assert
+		assert.NoError(t, err)
+		assert.Empty(t, *published)
+	})
+}
+
+func assertStream(t *testing.T, es *eventstore.InMemoryEventStore, id cqrs.Identifier, want ...cqrs.DomainEvent) {
+	t.Helper()
+
+	got, err := es.LoadEventsFor(id)
+	assert.NoError(t, err)
+
+	if len(want) == 0 {
+		assert.Empty(t, got)
+
+		return
+	}
+
+	assert.Equal(t, want, got)
+}
+
+func createUnitOfWork() (*unitofwork.UnitOfWork, *eventstore.InMemoryEventStore, *[]cqrs.DomainEvent) {
+	published := &[]cqrs.DomainEvent{}
+	es := eventstore.NewInInMemoryEventStore(&evnbustest.EventPublisherMock{
+		Publisher: func(e ...cqrs.DomainEvent) error {
+			*published = append(*published, e...)
+
+			return nil
+		},
+	})
+
+	return unitofwork.New(aggstore.NewStore(es, createAggregateFactory())), es, published
+}
+
+func createAggregateFactory() *aggregate.Factory {
+	aggFactory := aggregate.NewFactory()
+	for _, aggregateType := range []string{aggtest.TestAggregateType, otherAggregateType} {
+		aggFactory.RegisterAggregate(aggregateType, func(ID cqrs.Identifier) cqrs.ESAggregate {
+			return aggregate.FromAggregate(aggtest.NewTestAggregate(ID))
+		})
+	}
+
+	return aggFactory
+}
+
+func createAggregateStoreMock(want cqrs.ESAggregate, loadErr error) *aggstoretest.AggregateStoreMock {
+	return &aggstoretest.AggregateStoreMock{
+		Loader: func(aggregateID cqrs.Identifier, aggregateType string) (cqrs.ESAggregate, error) {
+			return want, loadErr
+		},
+	}
+}
+
+func createEventPublisherMock(err error) *evnbustest.EventPublisherMock {
+	return &evnbustest.EventPublisherMock{
+		Publisher: func(e ...cqrs.DomainEvent) error {
+			return err
+		},
+	}
+}